@@ -0,0 +1,145 @@
+package wex
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Client holds the connection settings shared by PublicAPI and TradeAPI, such as the base URLs, the HTTP client used for requests and the headers sent with every request.
+type Client struct {
+	HTTPClient *http.Client
+	PublicURL  string
+	TradeURL   string
+	UserAgent  string
+	Header     http.Header
+
+	NonceSource   NonceSource
+	PublicLimiter *RateLimiter
+	TradeLimiter  *RateLimiter
+	RetryPolicy   *RetryPolicy
+
+	pairInfo *pairInfoCache
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for all requests, e.g. to configure proxies, TLS settings or timeouts.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithPublicURL sets the base URL of Public API v3, e.g. "https://wex.nz/api/3/".
+func WithPublicURL(url string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(url, "/") {
+			url += "/"
+		}
+		c.PublicURL = url
+	}
+}
+
+// WithTradeURL sets the URL of Trade API, e.g. "https://wex.nz/tapi".
+func WithTradeURL(url string) Option {
+	return func(c *Client) {
+		c.TradeURL = url
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with every request. It replaces a header of the same name set by the API, e.g. Content-Type.
+func WithHeader(key string, value string) Option {
+	return func(c *Client) {
+		if c.Header == nil {
+			c.Header = make(http.Header)
+		}
+		c.Header.Add(key, value)
+	}
+}
+
+// NewClient creates a Client with default settings modified by the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		HTTPClient: http.DefaultClient,
+		PublicURL:  apiURL,
+		TradeURL:   tradeURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// New creates an API whose PublicAPI and TradeAPI share a Client configured with the given options.
+func New(opts ...Option) *API {
+	c := NewClient(opts...)
+	return &API{
+		Public: PublicAPI{client: c},
+		Trade:  TradeAPI{client: c, state: &tradeState{}},
+	}
+}
+
+// NewPublicAPI creates a PublicAPI configured with the given options.
+func NewPublicAPI(opts ...Option) *PublicAPI {
+	return &PublicAPI{client: NewClient(opts...)}
+}
+
+// NewTradeAPI creates a TradeAPI with API key and secret configured with the given options.
+func NewTradeAPI(key string, secret string, opts ...Option) *TradeAPI {
+	return &TradeAPI{API_KEY: key, API_SECRET: secret, client: NewClient(opts...), state: &tradeState{}}
+}
+
+var defaultClient = NewClient()
+
+// clientOrDefault returns c, or the default client when c is nil so that zero values of PublicAPI and TradeAPI keep working.
+func clientOrDefault(c *Client) *Client {
+	if c == nil {
+		return defaultClient
+	}
+	return c
+}
+
+// publicURL returns the base URL of Public API with a trailing "/", also for a PublicURL set without WithPublicURL.
+func (c *Client) publicURL() string {
+	if c.PublicURL == "" {
+		return apiURL
+	}
+	if !strings.HasSuffix(c.PublicURL, "/") {
+		return c.PublicURL + "/"
+	}
+	return c.PublicURL
+}
+
+func (c *Client) tradeURL() string {
+	if c.TradeURL == "" {
+		return tradeURL
+	}
+	return c.TradeURL
+}
+
+// do sends req after adding the configured headers, which replace headers of the same name set for the request.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for key, values := range c.Header {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return httpClient.Do(req)
+}
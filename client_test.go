@@ -0,0 +1,97 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient(t *testing.T) {
+
+	Convey("API created with custom base URLs and headers", t, func() {
+
+		var publicReq, tradeReq *http.Request
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/tapi" {
+				tradeReq = r
+				w.Write([]byte(`{"success":1,"return":{"open_orders":2}}`))
+				return
+			}
+			publicReq = r
+			w.Write([]byte(`{"btc_usd":{"buy":10,"sell":11}}`))
+		}))
+		defer server.Close()
+
+		api := New(
+			WithHTTPClient(server.Client()),
+			WithPublicURL(server.URL+"/api/3"),
+			WithTradeURL(server.URL+"/tapi"),
+			WithUserAgent("go-wex-test"),
+			WithHeader("X-Test", "1"),
+			WithHeader("Accept", "application/x-test"),
+		)
+
+		Convey("Public API should use the configured URL and headers", func() {
+			ticker, err := api.Public.Ticker([]Pair{"btc_usd"})
+			So(err, ShouldBeNil)
+			So(ticker["btc_usd"].Sell.String(), ShouldEqual, "11")
+			So(publicReq.URL.Path, ShouldEqual, "/api/3/ticker/btc_usd")
+			So(publicReq.Header.Get("User-Agent"), ShouldEqual, "go-wex-test")
+			So(publicReq.Header.Get("X-Test"), ShouldEqual, "1")
+			So(publicReq.Header["Accept"], ShouldResemble, []string{"application/x-test"})
+		})
+
+		Convey("Trade API should use the configured URL and headers", func() {
+			info, err := api.Trade.GetInfoAuth("key", "secret")
+			So(err, ShouldBeNil)
+			So(info.OpenOrders, ShouldEqual, 2)
+			So(tradeReq.Header.Get("Key"), ShouldEqual, "key")
+			So(tradeReq.Header.Get("User-Agent"), ShouldEqual, "go-wex-test")
+			So(tradeReq.Header.Get("X-Test"), ShouldEqual, "1")
+		})
+	})
+
+	Convey("Zero value APIs use the default client", t, func() {
+		So(clientOrDefault(PublicAPI{}.client).publicURL(), ShouldEqual, apiURL)
+		So(clientOrDefault(TradeAPI{}.client).tradeURL(), ShouldEqual, tradeURL)
+	})
+
+	Convey("Public URL should end with a slash", t, func() {
+		So((&Client{PublicURL: "https://example.com/api/3"}).publicURL(), ShouldEqual, "https://example.com/api/3/")
+		So((&Client{PublicURL: "https://example.com/api/3/"}).publicURL(), ShouldEqual, "https://example.com/api/3/")
+	})
+}
+
+func TestContext(t *testing.T) {
+
+	Convey("Requests with a deadline against a hung server", t, func() {
+
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		api := New(WithPublicURL(server.URL), WithTradeURL(server.URL))
+
+		Convey("Public API call should return the context error", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err := api.Public.TickerContext(ctx, []Pair{"btc_usd"})
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		})
+
+		Convey("Trade API call should return the context error", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err := api.Trade.GetInfoContext(ctx)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+		})
+	})
+}
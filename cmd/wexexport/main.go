@@ -0,0 +1,229 @@
+// Command wexexport exports trade history, transaction history, trades and order books of WEX as CSV, JSON Lines or indented JSON.
+//
+// Usage:
+//
+//	wexexport [flags] trade-history|transactions|trades|depth
+//
+// Trade API keys are read from the WEX_KEY and WEX_SECRET environment variables, or the -key and -secret flags.
+// For example, to export all trades of the account on btc_usd since 2017:
+//
+//	WEX_KEY=... WEX_SECRET=... wexexport -format csv -pair btc_usd -since 2017-01-01 trade-history > trades.csv
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+	"github.com/onuryilmaz/go-wex/export"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	cancel()
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "wexexport:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command line args, writing the export to stdout and usage to stderr.
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("wexexport", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "csv", "output format: csv, jsonl or json")
+	output := flags.String("o", "", "output file instead of standard output")
+	pairs := flags.String("pair", "", "comma separated pairs, e.g. btc_usd,ltc_usd; required for trades and depth")
+	limit := flags.Int("limit", 0, "number of trades or order book levels per pair")
+	since := flags.String("since", "", "start of history as date (2006-01-02) or RFC 3339 time")
+	end := flags.String("end", "", "end of history as date (2006-01-02) or RFC 3339 time")
+	key := flags.String("key", "", "Trade API key, WEX_KEY by default")
+	secret := flags.String("secret", "", "Trade API secret, WEX_SECRET by default")
+	publicURL := flags.String("public-url", "", "Public API URL")
+	tradeURL := flags.String("trade-url", "", "Trade API URL")
+	location := flags.String("tz", "UTC", "time zone of timestamps, e.g. Local or Europe/Istanbul")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: wexexport [flags] trade-history|transactions|trades|depth")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	command := flags.Arg(0)
+	columns, ok := commandColumns[command]
+	if !ok {
+		return fmt.Errorf("unknown command %q", command)
+	}
+	// the environment is read after parsing so that the usage does not print the keys as defaults
+	if *key == "" {
+		*key = os.Getenv("WEX_KEY")
+	}
+	if *secret == "" {
+		*secret = os.Getenv("WEX_SECRET")
+	}
+
+	outputFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+	tz, err := time.LoadLocation(*location)
+	if err != nil {
+		return err
+	}
+	filter := wex.HistoryFilter{}
+	if filter.Since, err = parseTime(*since); err != nil {
+		return err
+	}
+	if filter.End, err = parseTime(*end); err != nil {
+		return err
+	}
+	var pairList []wex.Pair
+	if *pairs != "" {
+		for _, s := range strings.Split(*pairs, ",") {
+			pair, err := wex.ParsePair(strings.TrimSpace(s))
+			if err != nil {
+				return err
+			}
+			pairList = append(pairList, pair)
+		}
+	}
+
+	var opts []wex.Option
+	if *publicURL != "" {
+		opts = append(opts, wex.WithPublicURL(*publicURL))
+	}
+	if *tradeURL != "" {
+		opts = append(opts, wex.WithTradeURL(*tradeURL))
+	}
+	api := wex.New(opts...)
+	api.Trade.Auth(*key, *secret)
+
+	write := func(out io.Writer) error {
+		w := export.NewWriter(out, outputFormat, columns)
+		w.Location = tz
+		if err := exportCommand(ctx, w, command, api, filter, pairList, *limit); err != nil {
+			return err
+		}
+		return w.Close()
+	}
+	if *output == "" {
+		return write(stdout)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// exportCommand writes the rows of the command to w.
+func exportCommand(ctx context.Context, w *export.Writer, command string, api *wex.API, filter wex.HistoryFilter, pairList []wex.Pair, limit int) error {
+	var err error
+	switch command {
+	case "trade-history":
+		err = exportTradeHistory(ctx, w, &api.Trade, filter, pairList)
+	case "transactions":
+		err = exportTransactions(ctx, w, &api.Trade, filter)
+	case "trades":
+		err = exportPublic(pairList, func() error {
+			trades, err := api.Public.TradesContext(ctx, pairList, limit)
+			if err != nil {
+				return err
+			}
+			return export.WriteTrades(w, trades)
+		})
+	case "depth":
+		err = exportPublic(pairList, func() error {
+			depth, err := api.Public.DepthContext(ctx, pairList, limit)
+			if err != nil {
+				return err
+			}
+			return export.WriteDepth(w, depth)
+		})
+	}
+	return err
+}
+
+// commandColumns are the columns of the rows exported by each command.
+var commandColumns = map[string][]string{
+	"trade-history": export.TradeHistoryColumns,
+	"transactions":  export.TransactionColumns,
+	"trades":        export.TradeColumns,
+	"depth":         export.DepthColumns,
+}
+
+func exportTradeHistory(ctx context.Context, w *export.Writer, tapi *wex.TradeAPI, filter wex.HistoryFilter, pairs []wex.Pair) error {
+	if len(pairs) == 0 {
+		pairs = []wex.Pair{""}
+	}
+	for _, pair := range pairs {
+		it := tapi.IterateTradeHistory(filter, pair)
+		for it.Next(ctx) {
+			if err := w.Write(export.TradeHistoryRow(it.Item())); err != nil {
+				return err
+			}
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportTransactions(ctx context.Context, w *export.Writer, tapi *wex.TradeAPI, filter wex.HistoryFilter) error {
+	it := tapi.IterateTransactionHistory(filter)
+	for it.Next(ctx) {
+		if err := w.Write(export.TransactionRow(it.Item())); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func exportPublic(pairs []wex.Pair, fn func() error) error {
+	if len(pairs) == 0 {
+		return errors.New("-pair is required")
+	}
+	return fn()
+}
+
+// parseTime parses a date such as 2017-07-14 or an RFC 3339 time. Empty s is the zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected 2006-01-02 or RFC 3339", s)
+	}
+	return t, nil
+}
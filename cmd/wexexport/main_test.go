@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRun(t *testing.T) {
+
+	Convey("Exporting from a server", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			switch {
+			case r.URL.Path == "/trades/btc_usd":
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"btc_usd":[{"type":"bid","price":101,"amount":0.2,"tid":1,"timestamp":1500000000}]}`))
+			case values.Get("method") == "TransHistory" && values.Get("from_id") == "":
+				w.Write([]byte(`{"success":1,"return":{"8":{"type":1,"amount":1000,"currency":"USD","desc":"Deposit","status":2,"timestamp":1500000000}}}`))
+			case values.Get("method") == "TransHistory":
+				w.Write([]byte(`{"success":0,"error":"no transactions"}`))
+			case values.Get("method") == "TradeHistory":
+				w.Write([]byte(`{"success":0,"error":"no trades"}`))
+			}
+		}))
+		defer server.Close()
+
+		var stdout, stderr bytes.Buffer
+		export := func(args ...string) error {
+			args = append([]string{"-public-url", server.URL, "-trade-url", server.URL}, args...)
+			return run(context.Background(), args, &stdout, &stderr)
+		}
+
+		Convey("Public trades should be exported", func() {
+			So(export("-format", "jsonl", "-pair", "btc_usd", "trades"), ShouldBeNil)
+			So(stdout.String(), ShouldEqual, `{"tid":1,"time":"2017-07-14T02:40:00Z","pair":"btc_usd","type":"bid","price":101,"amount":0.2}`+"\n")
+		})
+
+		Convey("Transactions should be exported", func() {
+			So(export("-key", "key", "-secret", "secret", "-since", "2017-01-01", "transactions"), ShouldBeNil)
+			So(stdout.String(), ShouldEqual, "id,time,type,currency,amount,status,description\n8,2017-07-14T02:40:00Z,deposit,USD,1000,successful,Deposit\n")
+		})
+
+		Convey("Empty history should be exported with a header", func() {
+			So(export("-key", "key", "-secret", "secret", "trade-history"), ShouldBeNil)
+			So(stdout.String(), ShouldEqual, "id,time,pair,type,rate,amount,total,order_id,is_your_order\n")
+		})
+
+		Convey("Invalid arguments should be reported", func() {
+			So(export("trades"), ShouldNotBeNil)
+			So(export("-format", "xml", "depth"), ShouldNotBeNil)
+			So(export("-since", "yesterday", "transactions"), ShouldNotBeNil)
+			So(export("orders"), ShouldNotBeNil)
+			So(export(), ShouldNotBeNil)
+			So(stderr.String(), ShouldContainSubstring, "usage: wexexport")
+		})
+
+		Convey("Output file should be written and kept for an unknown command", func() {
+			dir, err := ioutil.TempDir("", "wexexport")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			output := filepath.Join(dir, "trades.jsonl")
+
+			So(export("-o", output, "-format", "jsonl", "-pair", "btc_usd", "trades"), ShouldBeNil)
+			So(export("-o", output, "orders"), ShouldNotBeNil)
+			data, err := ioutil.ReadFile(output)
+			So(err, ShouldBeNil)
+			So(string(data), ShouldStartWith, `{"tid":1,`)
+			So(stdout.Len(), ShouldEqual, 0)
+
+			So(export("-o", filepath.Join(dir, "none.csv"), "orders"), ShouldNotBeNil)
+			_, err = os.Stat(filepath.Join(dir, "none.csv"))
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+
+		Convey("Usage should not print the keys of the environment", func() {
+			os.Setenv("WEX_SECRET", "environment-secret")
+			defer os.Unsetenv("WEX_SECRET")
+
+			So(export("-h"), ShouldNotBeNil)
+			So(stderr.String(), ShouldContainSubstring, "WEX_SECRET")
+			So(stderr.String(), ShouldNotContainSubstring, "environment-secret")
+		})
+	})
+}
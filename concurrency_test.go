@@ -0,0 +1,75 @@
+package wex
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConcurrentTradeAPI(t *testing.T) {
+
+	Convey("Trade API used from multiple goroutines", t, func() {
+
+		var mu sync.Mutex
+		var nonces []int64
+		badSigns := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			nonce, _ := strconv.ParseInt(values.Get("nonce"), 10, 64)
+
+			mu.Lock()
+			nonces = append(nonces, nonce)
+			if r.Header.Get("Sign") != sign("secret", string(body)) {
+				badSigns++
+			}
+			mu.Unlock()
+
+			if values.Get("method") == "ActiveOrders" {
+				w.Write([]byte(`{"success":0,"error":"no orders"}`))
+				return
+			}
+			w.Write([]byte(`{"success":1,"return":{"order_id":1}}`))
+		}))
+		defer server.Close()
+
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+
+		const workers, requests = 8, 25
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < requests; j++ {
+					switch (i + j) % 3 {
+					case 0:
+						tapi.Trade("btc_usd", "buy", 900, 1)
+					case 1:
+						tapi.ActiveOrders("btc_usd")
+					default:
+						tapi.Auth("key", "secret")
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		Convey("Requests should reach the server with strictly increasing nonces", func() {
+			So(len(nonces), ShouldBeGreaterThan, 0)
+			for i := 1; i < len(nonces); i++ {
+				So(nonces[i], ShouldBeGreaterThan, nonces[i-1])
+			}
+		})
+
+		Convey("Every request should be signed with the secret", func() {
+			So(badSigns, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,266 @@
+package wex
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number used for prices, amounts and balances, so that values received from and sent to the exchange are not subject to floating point rounding.
+//
+// The zero value is 0. Decimal values are immutable, arithmetic methods return new values.
+// Decimal values must be compared with Cmp or Equal: == compares the internal pointers and reports equal values as different.
+type Decimal struct {
+	rat *big.Rat
+}
+
+// divisionPlaces is the number of decimal places kept when a value has no finite decimal representation, e.g. a result of Div.
+const divisionPlaces = 18
+
+// maxExponent bounds the exponent of parsed decimals, as big.Rat allocates the full power of ten.
+const maxExponent = 1000
+
+// decimalPattern matches plain decimal numbers with optional exponent, rejecting other literals accepted by big.Rat such as "0x10", "1_000" or "1/3".
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)(?:[eE]([+-]?[0-9]+))?$`)
+
+var (
+	zeroRat = new(big.Rat)
+	ten     = big.NewInt(10)
+)
+
+// NewDecimal returns value * 10^exp, e.g. NewDecimal(1, -8) is 0.00000001.
+func NewDecimal(value int64, exp int) Decimal {
+	r := new(big.Rat).SetInt64(value)
+	scale := new(big.Rat).SetInt(pow10(abs(exp)))
+	if exp < 0 {
+		return Decimal{r.Quo(r, scale)}
+	}
+	return Decimal{r.Mul(r, scale)}
+}
+
+// NewDecimalFromInt returns value as Decimal.
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{new(big.Rat).SetInt64(value)}
+}
+
+// NewDecimalFromFloat returns the shortest decimal representation of value, e.g. 0.1 for 0.1 rather than its exact binary value.
+// NaN and infinities are returned as 0.
+func NewDecimalFromFloat(value float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	return d
+}
+
+// ParseDecimal parses a plain decimal number such as "12.345" or "1e-8". Go literals such as "0x10" or "1_000" and exponents beyond ±1000 are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	match := decimalPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if match[3] != "" {
+		if exp, err := strconv.Atoi(match[3]); err != nil || exp > maxExponent || exp < -maxExponent {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: exponent out of range", s)
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s cannot be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return zeroRat
+	}
+	return d.rat
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal {
+	return Decimal{new(big.Rat).Add(d.value(), e.value())}
+}
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal {
+	return Decimal{new(big.Rat).Sub(d.value(), e.value())}
+}
+
+// Mul returns d * e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{new(big.Rat).Mul(d.value(), e.value())}
+}
+
+// Div returns d / e. It panics if e is zero, so divisors which may be zero must be checked with IsZero first.
+func (d Decimal) Div(e Decimal) Decimal {
+	return Decimal{new(big.Rat).Quo(d.value(), e.value())}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{new(big.Rat).Neg(d.value())}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{new(big.Rat).Abs(d.value())}
+}
+
+// Cmp compares d and e and returns -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.value().Cmp(e.value())
+}
+
+// Equal reports whether d and e are equal.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Cmp(e) == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Float64 returns the nearest float64 value of d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.value().Float64()
+	return f
+}
+
+// Round returns d rounded to places decimal places, with halves rounded away from zero.
+func (d Decimal) Round(places int) Decimal {
+	return d.scale(places, true)
+}
+
+// Truncate returns d with the decimal places after places dropped, i.e. rounded towards zero.
+func (d Decimal) Truncate(places int) Decimal {
+	return d.scale(places, false)
+}
+
+func (d Decimal) scale(places int, round bool) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	scale := pow10(places)
+	num := new(big.Int).Mul(d.value().Num(), scale)
+	den := d.value().Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if round && new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+	return Decimal{new(big.Rat).SetFrac(quo, scale)}
+}
+
+// Places returns the number of decimal places of d, e.g. 3 for 1.125.
+// Values without a finite decimal representation have 18 decimal places.
+func (d Decimal) Places() int {
+	den := new(big.Int).Set(d.value().Denom())
+
+	twos := 0
+	for den.Bit(0) == 0 {
+		den.Rsh(den, 1)
+		twos++
+	}
+
+	fives := 0
+	five := big.NewInt(5)
+	quo, rem := new(big.Int), new(big.Int)
+	for {
+		quo.QuoRem(den, five, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		den.Set(quo)
+		fives++
+	}
+
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return divisionPlaces
+	}
+	if twos > fives {
+		return twos
+	}
+	return fives
+}
+
+// String returns d in decimal notation without trailing zeros, e.g. "0.00000001".
+func (d Decimal) String() string {
+	places := d.Places()
+	s := d.value().FloatString(places)
+	if places == divisionPlaces && strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		if s == "-0" {
+			s = "0"
+		}
+	}
+	return s
+}
+
+// StringFixed returns d rounded to places decimal places in decimal notation, e.g. "1.500" for 1.5 and 3 places.
+func (d Decimal) StringFixed(places int) string {
+	return d.Round(places).value().FloatString(places)
+}
+
+// MarshalJSON encodes d as JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes d from JSON number or string.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
+
+// MarshalText encodes d in decimal notation.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes d from decimal notation. Empty text is decoded as 0.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(ten, big.NewInt(int64(n)), nil)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
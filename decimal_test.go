@@ -0,0 +1,124 @@
+package wex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDecimal(t *testing.T) {
+
+	Convey("Decimal arithmetic should be exact", t, func() {
+		a := MustParseDecimal("0.1")
+		b := MustParseDecimal("0.2")
+		So(a.Add(b).String(), ShouldEqual, "0.3")
+		So(a.Add(b).Equal(MustParseDecimal("0.3")), ShouldBeTrue)
+		So(b.Sub(a).String(), ShouldEqual, "0.1")
+		So(a.Mul(b).String(), ShouldEqual, "0.02")
+		So(NewDecimalFromInt(1).Div(NewDecimalFromInt(3)).String(), ShouldEqual, "0.333333333333333333")
+		So(NewDecimal(1, -8).String(), ShouldEqual, "0.00000001")
+		So(NewDecimal(15, 2).String(), ShouldEqual, "1500")
+		So(Decimal{}.String(), ShouldEqual, "0")
+		So(a.Neg().String(), ShouldEqual, "-0.1")
+		So(a.Neg().Abs().Cmp(a), ShouldEqual, 0)
+	})
+
+	Convey("Floats should be converted to their shortest representation", t, func() {
+		So(NewDecimalFromFloat(0.1).String(), ShouldEqual, "0.1")
+		So(NewDecimalFromFloat(1e-8).String(), ShouldEqual, "0.00000001")
+		So(NewDecimalFromFloat(900).Float64(), ShouldEqual, 900)
+	})
+
+	Convey("Decimal should be rounded and truncated", t, func() {
+		d := MustParseDecimal("1.23456")
+		So(d.Round(3).String(), ShouldEqual, "1.235")
+		So(d.Truncate(3).String(), ShouldEqual, "1.234")
+		So(d.Neg().Round(3).String(), ShouldEqual, "-1.235")
+		So(MustParseDecimal("2.5").Round(0).String(), ShouldEqual, "3")
+		So(MustParseDecimal("1.5").StringFixed(3), ShouldEqual, "1.500")
+		So(d.Places(), ShouldEqual, 5)
+		So(NewDecimalFromInt(7).Places(), ShouldEqual, 0)
+	})
+
+	Convey("Invalid decimals should not be parsed", t, func() {
+		_, err := ParseDecimal("1/3")
+		So(err, ShouldNotBeNil)
+		_, err = ParseDecimal("abc")
+		So(err, ShouldNotBeNil)
+		for _, literal := range []string{"0x10", "0b101", "0o17", "1_000", "1e", ".", "", " 1", "Inf", "1e1001", "1e-1001", "1e99999999999999999999"} {
+			_, err = ParseDecimal(literal)
+			So(err, ShouldNotBeNil)
+		}
+
+		var v struct {
+			Amount Decimal `json:"amount"`
+		}
+		So(json.Unmarshal([]byte(`{"amount":"0x10"}`), &v), ShouldNotBeNil)
+	})
+
+	Convey("Plain decimals and exponents should be parsed", t, func() {
+		for literal, expected := range map[string]string{"-1.5": "-1.5", "+2": "2", ".5": "0.5", "5.": "5", "1E-8": "0.00000001", "0012.30": "12.3", "1e+3": "1000"} {
+			d, err := ParseDecimal(literal)
+			So(err, ShouldBeNil)
+			So(d.String(), ShouldEqual, expected)
+		}
+	})
+
+	Convey("Decimal should be decoded from JSON numbers and strings", t, func() {
+		var v struct {
+			Number Decimal `json:"number"`
+			String Decimal `json:"string"`
+			Null   Decimal `json:"null"`
+		}
+		err := json.Unmarshal([]byte(`{"number":0.12345678901234567890,"string":"1.00","null":null}`), &v)
+		So(err, ShouldBeNil)
+		So(v.Number.String(), ShouldEqual, "0.1234567890123456789")
+		So(v.String.String(), ShouldEqual, "1")
+		So(v.Null.IsZero(), ShouldBeTrue)
+
+		data, err := json.Marshal(v)
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `{"number":0.1234567890123456789,"string":1,"null":0}`)
+	})
+}
+
+func TestDecimalParameters(t *testing.T) {
+
+	Convey("Amounts of withdrawals and coupons should be sent exactly", t, func() {
+
+		var mu sync.Mutex
+		sent := map[string]url.Values{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			mu.Lock()
+			sent[values.Get("method")] = values
+			mu.Unlock()
+			w.Write([]byte(`{"success":1,"return":{}}`))
+		}))
+		defer server.Close()
+
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+
+		_, err := tapi.WithdrawCoinDecimal("BTC", MustParseDecimal("0.123456789012345678"), "address")
+		So(err, ShouldBeNil)
+		_, err = tapi.CreateCouponDecimal("USD", MustParseDecimal("1e-8"))
+		So(err, ShouldBeNil)
+		mu.Lock()
+		So(sent["WithdrawCoin"].Get("amount"), ShouldEqual, "0.123456789012345678")
+		So(sent["CreateCoupon"].Get("amount"), ShouldEqual, "0.00000001")
+		mu.Unlock()
+
+		_, err = tapi.CreateCoupon("USD", 0.1)
+		So(err, ShouldBeNil)
+		mu.Lock()
+		So(sent["CreateCoupon"].Get("amount"), ShouldEqual, "0.1")
+		mu.Unlock()
+	})
+}
@@ -0,0 +1,130 @@
+package wex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInsufficientDepth is returned when the order book does not hold enough volume for the requested amount.
+var ErrInsufficientDepth = errors.New("insufficient depth")
+
+// MarshalJSON encodes the level as [price, amount] like the exchange does.
+func (item DepthItem) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]Decimal{item.Price, item.Amount})
+}
+
+// UnmarshalJSON decodes the level from [price, amount].
+func (item *DepthItem) UnmarshalJSON(data []byte) error {
+	var values []Decimal
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if len(values) != 2 {
+		return fmt.Errorf("invalid depth item %s: expected [price, amount]", data)
+	}
+	item.Price, item.Amount = values[0], values[1]
+	return nil
+}
+
+// sortedAsks returns asks ordered by ascending price.
+func (d DepthPair) sortedAsks() []DepthItem {
+	asks := append([]DepthItem(nil), d.Asks...)
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price.Cmp(asks[j].Price) < 0 })
+	return asks
+}
+
+// sortedBids returns bids ordered by descending price.
+func (d DepthPair) sortedBids() []DepthItem {
+	bids := append([]DepthItem(nil), d.Bids...)
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price.Cmp(bids[j].Price) > 0 })
+	return bids
+}
+
+// BestAsk returns the ask with the lowest price, or false if there are no asks.
+func (d DepthPair) BestAsk() (DepthItem, bool) {
+	if len(d.Asks) == 0 {
+		return DepthItem{}, false
+	}
+	return d.sortedAsks()[0], true
+}
+
+// BestBid returns the bid with the highest price, or false if there are no bids.
+func (d DepthPair) BestBid() (DepthItem, bool) {
+	if len(d.Bids) == 0 {
+		return DepthItem{}, false
+	}
+	return d.sortedBids()[0], true
+}
+
+// Spread returns the difference between the best ask and the best bid, or false if either side is empty.
+func (d DepthPair) Spread() (Decimal, bool) {
+	ask, askOK := d.BestAsk()
+	bid, bidOK := d.BestBid()
+	if !askOK || !bidOK {
+		return Decimal{}, false
+	}
+	return ask.Price.Sub(bid.Price), true
+}
+
+// MidPrice returns the average of the best ask and the best bid, or false if either side is empty.
+func (d DepthPair) MidPrice() (Decimal, bool) {
+	ask, askOK := d.BestAsk()
+	bid, bidOK := d.BestBid()
+	if !askOK || !bidOK {
+		return Decimal{}, false
+	}
+	return ask.Price.Add(bid.Price).Div(NewDecimalFromInt(2)), true
+}
+
+// CumulativeAsks returns asks ordered by ascending price, with the amount of each level being the total amount up to and including it.
+func (d DepthPair) CumulativeAsks() []DepthItem {
+	return cumulative(d.sortedAsks())
+}
+
+// CumulativeBids returns bids ordered by descending price, with the amount of each level being the total amount up to and including it.
+func (d DepthPair) CumulativeBids() []DepthItem {
+	return cumulative(d.sortedBids())
+}
+
+func cumulative(levels []DepthItem) []DepthItem {
+	total := Decimal{}
+	for i := range levels {
+		total = total.Add(levels[i].Amount)
+		levels[i].Amount = total
+	}
+	return levels
+}
+
+// WeightedPrice returns the volume-weighted average price of an order of the given type and amount executed against the order book,
+// i.e. buy orders against asks and sell orders against bids. ErrInsufficientDepth is returned if the order book does not hold the amount.
+func (d DepthPair) WeightedPrice(orderType OrderType, amount Decimal) (Decimal, error) {
+	var levels []DepthItem
+	switch orderType {
+	case Buy:
+		levels = d.sortedAsks()
+	case Sell:
+		levels = d.sortedBids()
+	default:
+		return Decimal{}, fmt.Errorf("invalid order type %q", orderType)
+	}
+	if amount.Sign() <= 0 {
+		return Decimal{}, fmt.Errorf("invalid amount %v", amount)
+	}
+
+	remaining := amount
+	cost := Decimal{}
+	for _, level := range levels {
+		filled := level.Amount
+		if filled.Cmp(remaining) > 0 {
+			filled = remaining
+		}
+		cost = cost.Add(filled.Mul(level.Price))
+		remaining = remaining.Sub(filled)
+		if remaining.IsZero() {
+			return cost.Div(amount), nil
+		}
+	}
+	return Decimal{}, ErrInsufficientDepth
+}
@@ -0,0 +1,79 @@
+package wex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDepthPair(t *testing.T) {
+
+	Convey("Order book decoded from the exchange format", t, func() {
+
+		var depth DepthPair
+		err := json.Unmarshal([]byte(`{
+			"asks":[[101,1],[102,2],[103.5,3]],
+			"bids":[[100,1.5],[99,2],[98,4]]}`), &depth)
+		So(err, ShouldBeNil)
+
+		Convey("Levels should have price and amount", func() {
+			So(depth.Asks[1].Price.String(), ShouldEqual, "102")
+			So(depth.Asks[1].Amount.String(), ShouldEqual, "2")
+
+			data, err := json.Marshal(depth.Bids[:1])
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, `[[100,1.5]]`)
+		})
+
+		Convey("Best prices, spread and mid price should be calculated", func() {
+			ask, ok := depth.BestAsk()
+			So(ok, ShouldBeTrue)
+			So(ask.Price.String(), ShouldEqual, "101")
+			bid, ok := depth.BestBid()
+			So(ok, ShouldBeTrue)
+			So(bid.Price.String(), ShouldEqual, "100")
+
+			spread, _ := depth.Spread()
+			So(spread.String(), ShouldEqual, "1")
+			mid, _ := depth.MidPrice()
+			So(mid.String(), ShouldEqual, "100.5")
+		})
+
+		Convey("Cumulative depth should be calculated", func() {
+			asks := depth.CumulativeAsks()
+			So(asks[2].Price.String(), ShouldEqual, "103.5")
+			So(asks[2].Amount.String(), ShouldEqual, "6")
+			bids := depth.CumulativeBids()
+			So(bids[1].Amount.String(), ShouldEqual, "3.5")
+			So(depth.Bids[1].Amount.String(), ShouldEqual, "2")
+		})
+
+		Convey("Volume-weighted price should be calculated", func() {
+			price, err := depth.WeightedPrice(Buy, NewDecimalFromInt(2))
+			So(err, ShouldBeNil)
+			So(price.String(), ShouldEqual, "101.5")
+
+			price, err = depth.WeightedPrice(Sell, MustParseDecimal("2.5"))
+			So(err, ShouldBeNil)
+			So(price.String(), ShouldEqual, "99.6")
+
+			_, err = depth.WeightedPrice(Buy, NewDecimalFromInt(7))
+			So(errors.Is(err, ErrInsufficientDepth), ShouldBeTrue)
+		})
+	})
+
+	Convey("Malformed levels should not be decoded", t, func() {
+		var item DepthItem
+		So(json.Unmarshal([]byte(`[1]`), &item), ShouldNotBeNil)
+		So(json.Unmarshal([]byte(`{"price":1}`), &item), ShouldNotBeNil)
+	})
+
+	Convey("Empty order book should have no best prices", t, func() {
+		_, ok := DepthPair{}.BestAsk()
+		So(ok, ShouldBeFalse)
+		_, ok = DepthPair{}.Spread()
+		So(ok, ShouldBeFalse)
+	})
+}
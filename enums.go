@@ -0,0 +1,159 @@
+package wex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// OrderStatus is the status of an order.
+type OrderStatus int
+
+// Order statuses returned by Trade API.
+const (
+	OrderActive            OrderStatus = 0
+	OrderExecuted          OrderStatus = 1
+	OrderCanceled          OrderStatus = 2
+	OrderPartiallyCanceled OrderStatus = 3
+)
+
+var orderStatusNames = map[int]string{
+	0: "active",
+	1: "executed",
+	2: "canceled",
+	3: "partially canceled",
+}
+
+func (s OrderStatus) String() string {
+	return enumName(int(s), orderStatusNames, "OrderStatus")
+}
+
+// MarshalJSON encodes the status by name, or as number if it is unknown.
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int(s), orderStatusNames)
+}
+
+// UnmarshalJSON decodes the status from its number or name.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, orderStatusNames, "OrderStatus")
+	*s = OrderStatus(v)
+	return err
+}
+
+// TransactionType is the type of a transaction in the transaction history.
+type TransactionType int
+
+// Transaction types returned by Trade API.
+const (
+	TransactionDeposit    TransactionType = 1
+	TransactionWithdrawal TransactionType = 2
+	TransactionCredit     TransactionType = 4
+	TransactionDebit      TransactionType = 5
+)
+
+var transactionTypeNames = map[int]string{
+	1: "deposit",
+	2: "withdrawal",
+	4: "credit",
+	5: "debit",
+}
+
+func (t TransactionType) String() string {
+	return enumName(int(t), transactionTypeNames, "TransactionType")
+}
+
+// MarshalJSON encodes the type by name, or as number if it is unknown.
+func (t TransactionType) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int(t), transactionTypeNames)
+}
+
+// UnmarshalJSON decodes the type from its number or name.
+func (t *TransactionType) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, transactionTypeNames, "TransactionType")
+	*t = TransactionType(v)
+	return err
+}
+
+// TransactionStatus is the status of a transaction in the transaction history.
+type TransactionStatus int
+
+// Transaction statuses returned by Trade API.
+const (
+	TransactionCanceled     TransactionStatus = 0
+	TransactionWaiting      TransactionStatus = 1
+	TransactionSuccessful   TransactionStatus = 2
+	TransactionNotConfirmed TransactionStatus = 3
+)
+
+var transactionStatusNames = map[int]string{
+	0: "canceled",
+	1: "waiting",
+	2: "successful",
+	3: "not confirmed",
+}
+
+func (s TransactionStatus) String() string {
+	return enumName(int(s), transactionStatusNames, "TransactionStatus")
+}
+
+// MarshalJSON encodes the status by name, or as number if it is unknown.
+func (s TransactionStatus) MarshalJSON() ([]byte, error) {
+	return marshalEnum(int(s), transactionStatusNames)
+}
+
+// UnmarshalJSON decodes the status from its number or name.
+func (s *TransactionStatus) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalEnum(data, transactionStatusNames, "TransactionStatus")
+	*s = TransactionStatus(v)
+	return err
+}
+
+// Flag is a boolean sent by the exchange as 0 or 1.
+type Flag bool
+
+// UnmarshalJSON decodes the flag from 0, 1, true or false.
+func (f *Flag) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "1", "true", `"1"`:
+		*f = true
+	case "0", "false", `"0"`, "null":
+		*f = false
+	default:
+		return fmt.Errorf("invalid flag %s", data)
+	}
+	return nil
+}
+
+func enumName(v int, names map[int]string, typeName string) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return typeName + "(" + strconv.Itoa(v) + ")"
+}
+
+// marshalEnum encodes a known enum value by name and an unknown one as number, so that every value can be decoded by unmarshalEnum.
+func marshalEnum(v int, names map[int]string) ([]byte, error) {
+	if name, ok := names[v]; ok {
+		return json.Marshal(name)
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalEnum decodes an enum value from its number or, for values encoded with MarshalJSON, from its name.
+func unmarshalEnum(data []byte, names map[int]string, typeName string) (int, error) {
+	var v int
+	if err := json.Unmarshal(data, &v); err == nil {
+		return v, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return 0, fmt.Errorf("invalid %v %s", typeName, data)
+	}
+	for v, n := range names {
+		if n == name {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %v %q", typeName, name)
+}
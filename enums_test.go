@@ -0,0 +1,69 @@
+package wex
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEnums(t *testing.T) {
+
+	Convey("Order items should be decoded with typed status and flags", t, func() {
+		var item struct {
+			Order       OrderInfoItem
+			Transaction TransactionHistoryItem
+			Trade       TradeHistoryItem
+			Pair        InfoPair
+		}
+		err := json.Unmarshal([]byte(`{
+			"Order":{"pair":"btc_usd","type":"sell","status":3},
+			"Transaction":{"type":2,"status":2},
+			"Trade":{"is_your_order":1},
+			"Pair":{"hidden":0}}`), &item)
+		So(err, ShouldBeNil)
+		So(item.Order.Status, ShouldEqual, OrderPartiallyCanceled)
+		So(item.Transaction.Type, ShouldEqual, TransactionWithdrawal)
+		So(item.Transaction.Status, ShouldEqual, TransactionSuccessful)
+		So(bool(item.Trade.IsYourOrder), ShouldBeTrue)
+		So(bool(item.Pair.Hidden), ShouldBeFalse)
+	})
+
+	Convey("Enums should have names", t, func() {
+		So(OrderActive.String(), ShouldEqual, "active")
+		So(OrderCanceled.String(), ShouldEqual, "canceled")
+		So(TransactionDeposit.String(), ShouldEqual, "deposit")
+		So(TransactionWaiting.String(), ShouldEqual, "waiting")
+		So(OrderStatus(7).String(), ShouldEqual, "OrderStatus(7)")
+	})
+
+	Convey("Enums should be encoded by name and decoded back", t, func() {
+		data, err := json.Marshal([]OrderStatus{OrderExecuted, OrderStatus(7)})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `["executed",7]`)
+
+		var decoded []OrderStatus
+		So(json.Unmarshal(data, &decoded), ShouldBeNil)
+		So(decoded, ShouldResemble, []OrderStatus{OrderExecuted, OrderStatus(7)})
+
+		var types []TransactionType
+		data, err = json.Marshal([]TransactionType{TransactionCredit, TransactionType(3)})
+		So(err, ShouldBeNil)
+		So(json.Unmarshal(data, &types), ShouldBeNil)
+		So(types, ShouldResemble, []TransactionType{TransactionCredit, TransactionType(3)})
+
+		var statuses []OrderStatus
+		So(json.Unmarshal([]byte(`["executed",2]`), &statuses), ShouldBeNil)
+		So(statuses, ShouldResemble, []OrderStatus{OrderExecuted, OrderCanceled})
+
+		var status TransactionType
+		So(json.Unmarshal([]byte(`"fee"`), &status), ShouldNotBeNil)
+	})
+
+	Convey("Invalid flags should not be decoded", t, func() {
+		var flag Flag
+		So(json.Unmarshal([]byte(`2`), &flag), ShouldNotBeNil)
+		So(json.Unmarshal([]byte(`true`), &flag), ShouldBeNil)
+		So(bool(flag), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,164 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Error classes returned by Public and Trade APIs. They are meant to be used with errors.Is, e.g.
+//
+//	if errors.Is(err, wex.ErrNoOrders) {
+//		// there are no active orders
+//	}
+var (
+	ErrInvalidNonce      = errors.New("invalid nonce")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrNoOrders          = errors.New("no orders")
+	ErrNoTrades          = errors.New("no trades")
+	ErrNoTransactions    = errors.New("no transactions")
+	ErrInvalidOrder      = errors.New("invalid order")
+	ErrInvalidPair       = errors.New("invalid pair")
+	ErrInvalidKey        = errors.New("invalid api key or sign")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrRateLimited       = errors.New("rate limited")
+	ErrHTTPStatus        = errors.New("unexpected HTTP status")
+	ErrMalformedResponse = errors.New("malformed response")
+)
+
+// errorClasses maps fragments of server error messages to error classes.
+var errorClasses = []struct {
+	fragment string
+	class    error
+}{
+	{"invalid nonce", ErrInvalidNonce},
+	{"not enough", ErrInsufficientFunds},
+	{"insufficient", ErrInsufficientFunds},
+	{"no orders", ErrNoOrders},
+	{"no trades", ErrNoTrades},
+	{"no transactions", ErrNoTransactions},
+	{"invalid order", ErrInvalidOrder},
+	{"bad status", ErrInvalidOrder},
+	{"invalid pair", ErrInvalidPair},
+	{"invalid api key", ErrInvalidKey},
+	{"invalid sign", ErrInvalidKey},
+	{"permission", ErrPermissionDenied},
+	{"too many requests", ErrRateLimited},
+	{"requests too often", ErrRateLimited},
+	{"rate limit", ErrRateLimited},
+}
+
+// classify returns the error class of a server error message, or nil if it is unknown.
+func classify(msg string) error {
+	msg = strings.ToLower(msg)
+	for _, c := range errorClasses {
+		if strings.Contains(msg, c.fragment) {
+			return c.class
+		}
+	}
+	return nil
+}
+
+// TradeError is returned when Trade API responds with an error message.
+type TradeError struct {
+	Message string
+}
+
+func (e TradeError) Error() string {
+	return fmt.Sprintf("trading error: %v", e.Message)
+}
+
+// Is reports whether the error message belongs to the target error class.
+func (e TradeError) Is(target error) bool {
+	return target != nil && classify(e.Message) == target
+}
+
+// PublicError is returned when Public API responds with an error message.
+type PublicError struct {
+	Message string
+}
+
+func (e PublicError) Error() string {
+	return fmt.Sprintf("public api error: %v", e.Message)
+}
+
+// Is reports whether the error message belongs to the target error class.
+func (e PublicError) Is(target error) bool {
+	return target != nil && classify(e.Message) == target
+}
+
+// StatusError is returned when the server responds with an unexpected HTTP status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status: %v", e.Status)
+}
+
+// Is reports whether the target is ErrHTTPStatus, or ErrRateLimited for status 429.
+func (e StatusError) Is(target error) bool {
+	return target == ErrHTTPStatus || (target == ErrRateLimited && e.StatusCode == http.StatusTooManyRequests)
+}
+
+// RateLimitError is returned when a RateLimiter with NoWait set has no token available for a request.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e RateLimitError) Error() string {
+	return fmt.Sprintf("client rate limit exceeded, retry after %v", e.RetryAfter)
+}
+
+// Is reports whether the target is ErrRateLimited.
+func (e RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// DecodeError is returned when the response body cannot be decoded.
+type DecodeError struct {
+	Body []byte
+	Err  error
+}
+
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("malformed response: %v", e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrMalformedResponse.
+func (e DecodeError) Is(target error) bool {
+	return target == ErrMalformedResponse
+}
+
+// IsRetryable reports whether the request failed for a transient reason, i.e. rate limiting, server side failures or network errors, so that the same request may succeed later.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrInvalidNonce) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package wex
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorClasses(t *testing.T) {
+
+	Convey("Server error messages should be classified", t, func() {
+		So(errors.Is(TradeError{Message: "invalid nonce parameter; on key:10, you sent:'5', you should send:11"}, ErrInvalidNonce), ShouldBeTrue)
+		So(errors.Is(TradeError{Message: "It is not enough USD for purchase"}, ErrInsufficientFunds), ShouldBeTrue)
+		So(errors.Is(TradeError{Message: "no orders"}, ErrNoOrders), ShouldBeTrue)
+		So(errors.Is(TradeError{Message: "api key dont have trade permission"}, ErrPermissionDenied), ShouldBeTrue)
+		So(errors.Is(PublicError{Message: "Invalid pair name: btc_btc"}, ErrInvalidPair), ShouldBeTrue)
+		So(errors.Is(TradeError{Message: "no orders"}, ErrNoTrades), ShouldBeFalse)
+		So(errors.Is(TradeError{Message: "something new"}, ErrNoOrders), ShouldBeFalse)
+	})
+
+	Convey("Retryable errors should be recognized", t, func() {
+		So(IsRetryable(StatusError{StatusCode: 502}), ShouldBeTrue)
+		So(IsRetryable(StatusError{StatusCode: 429}), ShouldBeTrue)
+		So(IsRetryable(StatusError{StatusCode: 404}), ShouldBeFalse)
+		So(IsRetryable(TradeError{Message: "It is not enough USD for purchase"}), ShouldBeFalse)
+		So(IsRetryable(DecodeError{Err: errors.New("bad json")}), ShouldBeFalse)
+		So(IsRetryable(nil), ShouldBeFalse)
+	})
+}
+
+func TestErrorResponses(t *testing.T) {
+
+	Convey("Servers responding with errors", t, func() {
+
+		body := ""
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		api := New(WithPublicURL(server.URL), WithTradeURL(server.URL))
+
+		Convey("Public API error envelope should be returned as PublicError", func() {
+			body = `{"success":0,"error":"Invalid pair name: btc_btc"}`
+			_, err := api.Public.Ticker([]Pair{"btc_btc"})
+			So(err, ShouldResemble, PublicError{Message: "Invalid pair name: btc_btc"})
+			So(errors.Is(err, ErrInvalidPair), ShouldBeTrue)
+		})
+
+		Convey("Trade API error should be returned as TradeError", func() {
+			body = `{"success":0,"error":"no orders"}`
+			_, err := api.Trade.ActiveOrders("btc_usd")
+			So(err, ShouldResemble, TradeError{Message: "no orders"})
+			So(errors.Is(err, ErrNoOrders), ShouldBeTrue)
+		})
+
+		Convey("HTTP errors should be returned as StatusError", func() {
+			status = http.StatusBadGateway
+			body = `<html>Bad Gateway</html>`
+			_, err := api.Trade.GetInfo()
+			So(errors.Is(err, ErrHTTPStatus), ShouldBeTrue)
+			_, err = api.Public.Info()
+			So(errors.Is(err, ErrHTTPStatus), ShouldBeTrue)
+
+			var statusErr StatusError
+			So(errors.As(err, &statusErr), ShouldBeTrue)
+			So(statusErr.StatusCode, ShouldEqual, http.StatusBadGateway)
+		})
+
+		Convey("Malformed JSON should be returned as DecodeError", func() {
+			body = `{"success":1,`
+			_, err := api.Trade.GetInfo()
+			So(errors.Is(err, ErrMalformedResponse), ShouldBeTrue)
+			_, err = api.Public.Info()
+			So(errors.Is(err, ErrMalformedResponse), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,250 @@
+// Package export writes trade history, transaction history, trades and order books of WEX as CSV, JSON Lines or indented JSON.
+//
+// Rows of a type always have the same columns in the same order. Timestamps are written in RFC 3339 format, enums by name and decimals exactly.
+//
+// Example usage:
+//
+//	w := export.NewWriter(os.Stdout, export.CSV, export.TradeHistoryColumns)
+//	it := api.Trade.IterateTradeHistory(wex.HistoryFilter{}, "btc_usd")
+//	for it.Next(ctx) {
+//		if err := w.Write(export.TradeHistoryRow(it.Item())); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//	if err := w.Close(); err != nil {
+//		log.Fatal(err)
+//	}
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+)
+
+// Format is an output format.
+type Format string
+
+// Output formats.
+const (
+	CSV        Format = "csv"
+	JSONLines  Format = "jsonl"
+	PrettyJSON Format = "json"
+)
+
+// ParseFormat parses "csv", "jsonl" or "json".
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSONLines, PrettyJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("export: unknown format %q", s)
+}
+
+// Field is a named value of a row.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Row is a record with fields in column order.
+type Row []Field
+
+// Columns returns the names of the fields.
+func (r Row) Columns() []string {
+	columns := make([]string, len(r))
+	for i, field := range r {
+		columns[i] = field.Name
+	}
+	return columns
+}
+
+// Writer streams rows of the same columns in a format.
+// Close must be called after the last row to complete the output.
+type Writer struct {
+	// TimeFormat is the layout of timestamps, time.RFC3339 by default.
+	TimeFormat string
+	// Location is the time zone of timestamps, UTC by default.
+	Location *time.Location
+
+	w       io.Writer
+	format  Format
+	csv     *csv.Writer
+	columns []string
+	rows    int
+}
+
+// NewWriter returns a Writer writing to w in the format.
+// Rows must have the columns, e.g. TradeHistoryColumns, so that CSV output has a header even without rows.
+// With nil columns, the columns of the first row are used.
+func NewWriter(w io.Writer, format Format, columns []string) *Writer {
+	writer := &Writer{w: w, format: format, columns: columns}
+	if format == CSV {
+		writer.csv = csv.NewWriter(w)
+	}
+	return writer
+}
+
+// Write writes a row. Rows must have the columns of the Writer, or of the first row.
+func (w *Writer) Write(row Row) error {
+	if w.columns == nil {
+		w.columns = row.Columns()
+	}
+	if !equalColumns(w.columns, row) {
+		return fmt.Errorf("export: row columns %v differ from %v", row.Columns(), w.columns)
+	}
+	if w.rows == 0 {
+		if err := w.header(); err != nil {
+			return err
+		}
+	}
+	w.rows++
+
+	switch w.format {
+	case CSV:
+		record := make([]string, len(row))
+		for i, field := range row {
+			record[i] = w.text(field.Value)
+		}
+		return w.csv.Write(record)
+
+	case JSONLines:
+		data, err := w.object(row, "", "")
+		if err != nil {
+			return err
+		}
+		_, err = w.w.Write(append(data, '\n'))
+		return err
+
+	case PrettyJSON:
+		data, err := w.object(row, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		prefix := ",\n  "
+		if w.rows == 1 {
+			prefix = "[\n  "
+		}
+		_, err = io.WriteString(w.w, prefix+string(data))
+		return err
+	}
+	return fmt.Errorf("export: unknown format %q", w.format)
+}
+
+// Close completes the output, e.g. writes the CSV header if no rows were written or closes the JSON array, and flushes buffered data.
+// It does not close the underlying writer.
+func (w *Writer) Close() error {
+	switch w.format {
+	case CSV:
+		if w.rows == 0 {
+			if err := w.header(); err != nil {
+				return err
+			}
+		}
+		w.csv.Flush()
+		return w.csv.Error()
+	case PrettyJSON:
+		end := "\n]\n"
+		if w.rows == 0 {
+			end = "[]\n"
+		}
+		_, err := io.WriteString(w.w, end)
+		return err
+	}
+	return nil
+}
+
+// header writes the CSV header, if the columns are known.
+func (w *Writer) header() error {
+	if w.format != CSV || w.columns == nil {
+		return nil
+	}
+	return w.csv.Write(w.columns)
+}
+
+func equalColumns(columns []string, row Row) bool {
+	if len(columns) != len(row) {
+		return false
+	}
+	for i, field := range row {
+		if columns[i] != field.Name {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *Writer) time(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	layout, location := w.TimeFormat, w.Location
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	return t.In(location).Format(layout)
+}
+
+// text returns the value as CSV field.
+func (w *Writer) text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return w.time(v)
+	case wex.Time:
+		return w.time(v.Time)
+	}
+	return fmt.Sprint(value)
+}
+
+// object encodes the row as JSON object keeping the order of the fields.
+func (w *Writer) object(row Row, prefix string, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if indent != "" {
+			buf.WriteString("\n" + prefix + indent)
+		}
+
+		value := field.Value
+		switch v := value.(type) {
+		case time.Time:
+			value = w.time(v)
+		case wex.Time:
+			value = w.time(v.Time)
+		}
+
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		if indent != "" {
+			buf.WriteByte(' ')
+		}
+		buf.Write(data)
+	}
+	if indent != "" && len(row) > 0 {
+		buf.WriteString("\n" + prefix)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWriter(t *testing.T) {
+
+	Convey("Transaction history written in each format", t, func() {
+
+		var history wex.TransactionHistory
+		So(json.Unmarshal([]byte(`{
+			"9":{"type":2,"amount":0.5,"currency":"BTC","desc":"Withdraw, \"cold\" wallet","status":1,"timestamp":1500000100},
+			"8":{"type":1,"amount":1000,"currency":"USD","desc":"Deposit","status":2,"timestamp":1500000000}}`), &history), ShouldBeNil)
+		var buf bytes.Buffer
+
+		Convey("CSV should have a header and stable columns", func() {
+			w := NewWriter(&buf, CSV, TransactionColumns)
+			So(WriteTransactionHistory(w, history), ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "id,time,type,currency,amount,status,description\n"+
+				"8,2017-07-14T02:40:00Z,deposit,USD,1000,successful,Deposit\n"+
+				"9,2017-07-14T02:41:40Z,withdrawal,BTC,0.5,waiting,\"Withdraw, \"\"cold\"\" wallet\"\n")
+		})
+
+		Convey("CSV without rows should have a header of the given columns", func() {
+			w := NewWriter(&buf, CSV, TransactionColumns)
+			So(WriteTransactionHistory(w, wex.TransactionHistory{}), ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "id,time,type,currency,amount,status,description\n")
+
+			buf.Reset()
+			So(NewWriter(&buf, CSV, nil).Close(), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "")
+		})
+
+		Convey("JSON Lines should have an object per line in column order", func() {
+			w := NewWriter(&buf, JSONLines, nil)
+			w.Location = time.FixedZone("UTC+3", 3*60*60)
+			So(WriteTransactionHistory(w, history), ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+			lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+			So(len(lines), ShouldEqual, 2)
+			So(string(lines[0]), ShouldEqual, `{"id":8,"time":"2017-07-14T05:40:00+03:00","type":"deposit","currency":"USD","amount":1000,"status":"successful","description":"Deposit"}`)
+		})
+
+		Convey("Pretty JSON should be an indented array", func() {
+			w := NewWriter(&buf, PrettyJSON, nil)
+			So(WriteTransactionHistory(w, history), ShouldBeNil)
+			So(w.Close(), ShouldBeNil)
+
+			var decoded []map[string]interface{}
+			So(json.Unmarshal(buf.Bytes(), &decoded), ShouldBeNil)
+			So(len(decoded), ShouldEqual, 2)
+			So(decoded[1]["status"], ShouldEqual, "waiting")
+			So(buf.String(), ShouldStartWith, "[\n  {\n    \"id\": 8,\n    \"time\": \"2017-07-14T02:40:00Z\",")
+
+			buf.Reset()
+			So(NewWriter(&buf, PrettyJSON, nil).Close(), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "[]\n")
+		})
+
+		Convey("Rows with other columns should be rejected", func() {
+			w := NewWriter(&buf, JSONLines, nil)
+			So(WriteTransactionHistory(w, history), ShouldBeNil)
+			So(w.Write(TradeRow("btc_usd", wex.TradeItem{})), ShouldNotBeNil)
+
+			w = NewWriter(&buf, JSONLines, TradeColumns)
+			So(WriteTransactionHistory(w, history), ShouldNotBeNil)
+		})
+	})
+
+	Convey("Market data should be written sorted", t, func() {
+
+		var buf bytes.Buffer
+		w := NewWriter(&buf, CSV, nil)
+		depth := wex.Depth{"btc_usd": {
+			Asks: []wex.DepthItem{{Price: wex.NewDecimalFromInt(102), Amount: wex.NewDecimalFromInt(1)}, {Price: wex.NewDecimalFromInt(101), Amount: wex.NewDecimalFromInt(2)}},
+			Bids: []wex.DepthItem{{Price: wex.NewDecimalFromInt(99), Amount: wex.NewDecimalFromInt(3)}, {Price: wex.NewDecimalFromInt(100), Amount: wex.NewDecimalFromInt(4)}},
+		}}
+		So(WriteDepth(w, depth), ShouldBeNil)
+		So(w.Close(), ShouldBeNil)
+		So(buf.String(), ShouldEqual, "pair,side,level,price,amount\n"+
+			"btc_usd,ask,1,101,2\nbtc_usd,ask,2,102,1\nbtc_usd,bid,1,100,4\nbtc_usd,bid,2,99,3\n")
+
+		buf.Reset()
+		w = NewWriter(&buf, JSONLines, nil)
+		trades := wex.Trades{"btc_usd": {
+			{Type: "ask", Price: wex.NewDecimalFromInt(100), Amount: wex.MustParseDecimal("0.1"), TID: 2, Timestamp: wex.UnixTime(1500000001)},
+			{Type: "bid", Price: wex.NewDecimalFromInt(101), Amount: wex.MustParseDecimal("0.2"), TID: 1, Timestamp: wex.UnixTime(1500000000)},
+		}}
+		So(WriteTrades(w, trades), ShouldBeNil)
+		So(buf.String(), ShouldStartWith, `{"tid":1,"time":"2017-07-14T02:40:00Z","pair":"btc_usd","type":"bid","price":101,"amount":0.2}`)
+	})
+
+	Convey("Rows should have the columns of their type", t, func() {
+		So(TradeHistoryRow(wex.TradeHistoryItem{}).Columns(), ShouldResemble, TradeHistoryColumns)
+		So(TransactionRow(wex.TransactionHistoryItem{}).Columns(), ShouldResemble, TransactionColumns)
+		So(TradeRow("btc_usd", wex.TradeItem{}).Columns(), ShouldResemble, TradeColumns)
+		rows := DepthRows("btc_usd", wex.DepthPair{Asks: []wex.DepthItem{{}}})
+		So(rows[0].Columns(), ShouldResemble, DepthColumns)
+	})
+
+	Convey("Formats should be parsed", t, func() {
+		format, err := ParseFormat("JSONL")
+		So(err, ShouldBeNil)
+		So(format, ShouldEqual, JSONLines)
+		_, err = ParseFormat("xml")
+		So(err, ShouldNotBeNil)
+	})
+}
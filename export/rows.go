@@ -0,0 +1,141 @@
+package export
+
+import (
+	"sort"
+
+	wex "github.com/onuryilmaz/go-wex"
+)
+
+// Columns of the rows of each type, to be passed to NewWriter.
+var (
+	TradeHistoryColumns = []string{"id", "time", "pair", "type", "rate", "amount", "total", "order_id", "is_your_order"}
+	TransactionColumns  = []string{"id", "time", "type", "currency", "amount", "status", "description"}
+	TradeColumns        = []string{"tid", "time", "pair", "type", "price", "amount"}
+	DepthColumns        = []string{"pair", "side", "level", "price", "amount"}
+)
+
+// TradeHistoryRow returns the row of a trade of the account.
+func TradeHistoryRow(item wex.TradeHistoryItem) Row {
+	return Row{
+		{"id", item.ID},
+		{"time", item.Timestamp},
+		{"pair", item.Pair},
+		{"type", item.Type},
+		{"rate", item.Rate},
+		{"amount", item.Amount},
+		{"total", item.Rate.Mul(item.Amount)},
+		{"order_id", item.OrderID},
+		{"is_your_order", bool(item.IsYourOrder)},
+	}
+}
+
+// TransactionRow returns the row of a transaction of the account.
+func TransactionRow(item wex.TransactionHistoryItem) Row {
+	return Row{
+		{"id", item.ID},
+		{"time", item.Timestamp},
+		{"type", item.Type},
+		{"currency", item.Currency},
+		{"amount", item.Amount},
+		{"status", item.Status},
+		{"description", item.Description},
+	}
+}
+
+// TradeRow returns the row of a trade of the pair from Trades.
+func TradeRow(pair wex.Pair, item wex.TradeItem) Row {
+	return Row{
+		{"tid", item.TID},
+		{"time", item.Timestamp},
+		{"pair", pair},
+		{"type", item.Type},
+		{"price", item.Price},
+		{"amount", item.Amount},
+	}
+}
+
+// DepthRows returns the rows of the levels of an order book, asks in ascending and bids in descending order of price.
+// Level is the position of a price on its side, starting at 1 for the best price.
+func DepthRows(pair wex.Pair, depth wex.DepthPair) []Row {
+	var rows []Row
+	add := func(side string, levels []wex.DepthItem) {
+		for i, level := range levels {
+			rows = append(rows, Row{
+				{"pair", pair},
+				{"side", side},
+				{"level", i + 1},
+				{"price", level.Price},
+				{"amount", level.Amount},
+			})
+		}
+	}
+	asks := append([]wex.DepthItem(nil), depth.Asks...)
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price.Cmp(asks[j].Price) < 0 })
+	bids := append([]wex.DepthItem(nil), depth.Bids...)
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price.Cmp(bids[j].Price) > 0 })
+	add("ask", asks)
+	add("bid", bids)
+	return rows
+}
+
+// WriteTradeHistory writes the trades in chronological order.
+func WriteTradeHistory(w *Writer, history wex.TradeHistory) error {
+	for _, item := range history.Items() {
+		if err := w.Write(TradeHistoryRow(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTransactionHistory writes the transactions in chronological order.
+func WriteTransactionHistory(w *Writer, history wex.TransactionHistory) error {
+	for _, item := range history.Items() {
+		if err := w.Write(TransactionRow(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteTrades writes the trades of all pairs sorted by pair and TID.
+func WriteTrades(w *Writer, trades wex.Trades) error {
+	pairs := make([]wex.Pair, 0, len(trades))
+	for pair := range trades {
+		pairs = append(pairs, pair)
+	}
+	sortPairs(pairs)
+
+	for _, pair := range pairs {
+		items := append(wex.TradePair(nil), trades[pair]...)
+		sort.SliceStable(items, func(i, j int) bool { return items[i].TID < items[j].TID })
+		for _, item := range items {
+			if err := w.Write(TradeRow(pair, item)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteDepth writes the order books of all pairs sorted by pair.
+func WriteDepth(w *Writer, depth wex.Depth) error {
+	pairs := make([]wex.Pair, 0, len(depth))
+	for pair := range depth {
+		pairs = append(pairs, pair)
+	}
+	sortPairs(pairs)
+
+	for _, pair := range pairs {
+		for _, row := range DepthRows(pair, depth[pair]) {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortPairs(pairs []wex.Pair) {
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i] < pairs[j] })
+}
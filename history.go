@@ -0,0 +1,207 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// historyPageSize is the number of items requested per page when HistoryFilter.Count is not set or larger, the maximum allowed by Trade API.
+const historyPageSize = 1000
+
+// HistoryCursor is the position of a history iterator, i.e. the ID of the last item returned.
+// It can be persisted to resume iterating later with HistoryFilter.After.
+type HistoryCursor struct {
+	LastID int `json:"last_id"`
+}
+
+// After returns a copy of the filter starting after the cursor.
+func (f HistoryFilter) After(cursor HistoryCursor) HistoryFilter {
+	if cursor.LastID >= f.FromID {
+		f.FromID = cursor.LastID + 1
+	}
+	return f
+}
+
+// historyPager pages through history in ascending order of IDs, requesting the next page from the ID after the last one returned.
+type historyPager struct {
+	filter HistoryFilter
+	ids    []int
+	pos    int
+	last   int
+	done   bool
+	err    error
+}
+
+func newHistoryPager(filter HistoryFilter) historyPager {
+	if filter.Count <= 0 || filter.Count > historyPageSize {
+		filter.Count = historyPageSize
+	}
+	filter.From = 0
+	filter.Order = "ASC"
+	return historyPager{filter: filter, pos: -1, last: filter.FromID - 1}
+}
+
+// next advances to the next ID, calling fetch to request the IDs of the next page when the current one is exhausted.
+func (p *historyPager) next(ctx context.Context, fetch func(ctx context.Context, filter HistoryFilter) ([]int, error)) bool {
+	for {
+		if p.pos+1 < len(p.ids) {
+			p.pos++
+			p.last = p.ids[p.pos]
+			return true
+		}
+		if p.done || p.err != nil {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+
+		filter := p.filter
+		if p.last >= filter.FromID {
+			filter.FromID = p.last + 1
+		}
+		page, err := fetch(ctx, filter)
+		if err != nil {
+			p.err = err
+			return false
+		}
+		sort.Ints(page)
+
+		p.ids, p.pos = p.ids[:0], -1
+		for _, id := range page {
+			if id > p.last && (p.filter.EndID <= 0 || id <= p.filter.EndID) {
+				p.ids = append(p.ids, id)
+			}
+		}
+		if len(page) < filter.Count || len(p.ids) == 0 {
+			p.done = true
+		}
+	}
+}
+
+func (p *historyPager) cursor() HistoryCursor {
+	if p.last < 0 {
+		return HistoryCursor{}
+	}
+	return HistoryCursor{LastID: p.last}
+}
+
+// TradeHistoryIterator iterates over trade history in chronological order, requesting pages as needed.
+//
+//	it := tapi.IterateTradeHistory(wex.HistoryFilter{Since: since}, "btc_usd")
+//	for it.Next(ctx) {
+//		fmt.Println(it.ID(), it.Item())
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error, it.Cursor() tells where to resume
+//	}
+type TradeHistoryIterator struct {
+	tapi  *TradeAPI
+	pair  Pair
+	pager historyPager
+	page  TradeHistory
+}
+
+// IterateTradeHistory returns an iterator over trades of the pair matching the filter, or of all pairs if pair is empty.
+// Count of the filter is used as the page size, up to 1000, From and Order are ignored.
+func (tapi *TradeAPI) IterateTradeHistory(filter HistoryFilter, pair Pair) *TradeHistoryIterator {
+	return &TradeHistoryIterator{tapi: tapi, pair: pair, pager: newHistoryPager(filter)}
+}
+
+// Next advances to the next trade and reports whether there is one.
+// It returns false at the end of the history, on errors and when ctx is done.
+func (it *TradeHistoryIterator) Next(ctx context.Context) bool {
+	return it.pager.next(ctx, func(ctx context.Context, filter HistoryFilter) ([]int, error) {
+		page, err := it.tapi.TradeHistoryContext(ctx, filter, it.pair)
+		if errors.Is(err, ErrNoTrades) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		it.page = page
+
+		ids := make([]int, 0, len(page))
+		for _, item := range page {
+			ids = append(ids, item.ID)
+		}
+		return ids, nil
+	})
+}
+
+// ID returns the ID of the current trade.
+func (it *TradeHistoryIterator) ID() int {
+	return it.pager.last
+}
+
+// Item returns the current trade.
+func (it *TradeHistoryIterator) Item() TradeHistoryItem {
+	return it.page[strconv.Itoa(it.pager.last)]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *TradeHistoryIterator) Err() error {
+	return it.pager.err
+}
+
+// Cursor returns the position after the current trade.
+func (it *TradeHistoryIterator) Cursor() HistoryCursor {
+	return it.pager.cursor()
+}
+
+// TransactionHistoryIterator iterates over transaction history in chronological order, requesting pages as needed.
+type TransactionHistoryIterator struct {
+	tapi  *TradeAPI
+	pager historyPager
+	page  TransactionHistory
+}
+
+// IterateTransactionHistory returns an iterator over transactions matching the filter.
+// Count of the filter is used as the page size, up to 1000, From and Order are ignored.
+func (tapi *TradeAPI) IterateTransactionHistory(filter HistoryFilter) *TransactionHistoryIterator {
+	return &TransactionHistoryIterator{tapi: tapi, pager: newHistoryPager(filter)}
+}
+
+// Next advances to the next transaction and reports whether there is one.
+// It returns false at the end of the history, on errors and when ctx is done.
+func (it *TransactionHistoryIterator) Next(ctx context.Context) bool {
+	return it.pager.next(ctx, func(ctx context.Context, filter HistoryFilter) ([]int, error) {
+		page, err := it.tapi.TransactionHistoryContext(ctx, filter)
+		if errors.Is(err, ErrNoTransactions) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		it.page = page
+
+		ids := make([]int, 0, len(page))
+		for _, item := range page {
+			ids = append(ids, item.ID)
+		}
+		return ids, nil
+	})
+}
+
+// ID returns the ID of the current transaction.
+func (it *TransactionHistoryIterator) ID() int {
+	return it.pager.last
+}
+
+// Item returns the current transaction.
+func (it *TransactionHistoryIterator) Item() TransactionHistoryItem {
+	return it.page[strconv.Itoa(it.pager.last)]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *TransactionHistoryIterator) Err() error {
+	return it.pager.err
+}
+
+// Cursor returns the position after the current transaction.
+func (it *TransactionHistoryIterator) Cursor() HistoryCursor {
+	return it.pager.cursor()
+}
@@ -0,0 +1,148 @@
+package wex
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// historyServer serves trade and transaction history with IDs 1 to n, honoring from_id, end_id and count up to 1000 like the exchange.
+func historyServer(n int) (*httptest.Server, func() []url.Values) {
+	var mu sync.Mutex
+	var requests []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(body))
+		mu.Lock()
+		requests = append(requests, values)
+		mu.Unlock()
+
+		from, _ := strconv.Atoi(values.Get("from_id"))
+		end, _ := strconv.Atoi(values.Get("end_id"))
+		count, _ := strconv.Atoi(values.Get("count"))
+		if count <= 0 || count > 1000 {
+			count = 1000
+		}
+		if end == 0 || end > n {
+			end = n
+		}
+
+		var items []string
+		for id := from; id <= end && len(items) < count; id++ {
+			if id < 1 {
+				continue
+			}
+			if values.Get("method") == "TransHistory" {
+				items = append(items, fmt.Sprintf(`"%d":{"type":1,"amount":%d,"currency":"BTC","status":2,"timestamp":%d}`, id, id, 1500000000+id))
+			} else {
+				items = append(items, fmt.Sprintf(`"%d":{"pair":"btc_usd","type":"buy","amount":1,"rate":%d,"order_id":1,"timestamp":%d}`, id, 900+id, 1500000000+id))
+			}
+		}
+		if len(items) == 0 && values.Get("method") == "TransHistory" {
+			w.Write([]byte(`{"success":0,"error":"no transactions"}`))
+			return
+		}
+		if len(items) == 0 {
+			w.Write([]byte(`{"success":0,"error":"no trades"}`))
+			return
+		}
+		w.Write([]byte(`{"success":1,"return":{` + strings.Join(items, ",") + `}}`))
+	}))
+	return server, func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func TestHistoryIterators(t *testing.T) {
+
+	Convey("Page sizes above the limit of the exchange should be reduced", t, func() {
+
+		server, requests := historyServer(1500)
+		defer server.Close()
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+
+		it := tapi.IterateTradeHistory(HistoryFilter{Count: 5000}, "")
+		n := 0
+		for it.Next(context.Background()) {
+			n++
+		}
+		So(it.Err(), ShouldBeNil)
+		So(n, ShouldEqual, 1500)
+		So(requests()[0].Get("count"), ShouldEqual, "1000")
+	})
+
+	Convey("Iterating over history of 7 trades in pages of 3", t, func() {
+
+		server, requests := historyServer(7)
+		defer server.Close()
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+
+		Convey("All trades should be returned in order", func() {
+			it := tapi.IterateTradeHistory(HistoryFilter{Count: 3, Order: "DESC"}, "btc_usd")
+			var ids []int
+			for it.Next(context.Background()) {
+				ids = append(ids, it.ID())
+				So(it.Item().Rate.String(), ShouldEqual, strconv.Itoa(900+it.ID()))
+			}
+			So(it.Err(), ShouldBeNil)
+			So(ids, ShouldResemble, []int{1, 2, 3, 4, 5, 6, 7})
+			So(it.Cursor(), ShouldResemble, HistoryCursor{LastID: 7})
+
+			pages := requests()
+			So(len(pages), ShouldEqual, 3)
+			So(pages[1].Get("from_id"), ShouldEqual, "4")
+			So(pages[1].Get("order"), ShouldEqual, "ASC")
+			So(pages[1].Get("pair"), ShouldEqual, "btc_usd")
+		})
+
+		Convey("Iteration should stop at EndID", func() {
+			it := tapi.IterateTradeHistory(HistoryFilter{Count: 3, FromID: 2, EndID: 5}, "")
+			var ids []int
+			for it.Next(context.Background()) {
+				ids = append(ids, it.ID())
+			}
+			So(ids, ShouldResemble, []int{2, 3, 4, 5})
+		})
+
+		Convey("Iteration should resume from a cursor", func() {
+			it := tapi.IterateTransactionHistory(HistoryFilter{Count: 3})
+			for i := 0; i < 4 && it.Next(context.Background()); i++ {
+			}
+			cursor := it.Cursor()
+			So(cursor.LastID, ShouldEqual, 4)
+
+			resumed := tapi.IterateTransactionHistory(HistoryFilter{Count: 3}.After(cursor))
+			var ids []int
+			for resumed.Next(context.Background()) {
+				ids = append(ids, resumed.ID())
+				So(resumed.Item().Amount.String(), ShouldEqual, strconv.Itoa(resumed.ID()))
+			}
+			So(resumed.Err(), ShouldBeNil)
+			So(ids, ShouldResemble, []int{5, 6, 7})
+		})
+
+		Convey("Canceled context should stop the iteration", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			it := tapi.IterateTradeHistory(HistoryFilter{Count: 3}, "")
+			for it.Next(ctx) {
+				if it.ID() == 3 {
+					cancel()
+				}
+			}
+			So(it.Err(), ShouldEqual, context.Canceled)
+			So(it.Cursor().LastID, ShouldEqual, 3)
+		})
+	})
+}
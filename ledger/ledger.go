@@ -0,0 +1,349 @@
+// Package ledger keeps a local copy of the trade and transaction history of a WEX account and reconstructs balances from it.
+//
+// Example usage:
+//
+//	book, err := ledger.Open(ledger.NewFileStore("ledger.jsonl"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	book.Fees = info.Fees()
+//
+//	if _, err := book.Sync(ctx, &api.Trade); err != nil {
+//		log.Fatal(err)
+//	}
+//	discrepancies, err := book.Check(ctx, &api.Trade, wex.NewDecimal(1, -8))
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+)
+
+// Kind is the source of a ledger entry.
+type Kind string
+
+// Sources of ledger entries.
+const (
+	KindTrade       Kind = "trade"
+	KindTransaction Kind = "transaction"
+)
+
+// Entry is a trade or a transaction of the account, identified by its kind and ID.
+type Entry struct {
+	Kind        Kind                        `json:"kind"`
+	ID          int                         `json:"id"`
+	Time        wex.Time                    `json:"time"`
+	Trade       *wex.TradeHistoryItem       `json:"trade,omitempty"`
+	Transaction *wex.TransactionHistoryItem `json:"transaction,omitempty"`
+}
+
+// UnmarshalJSON decodes the entry and sets the ID of its trade or transaction, which is not part of their JSON.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	type entry Entry
+	if err := json.Unmarshal(data, (*entry)(e)); err != nil {
+		return err
+	}
+	if e.Trade != nil {
+		e.Trade.ID = e.ID
+	}
+	if e.Transaction != nil {
+		e.Transaction.ID = e.ID
+	}
+	return nil
+}
+
+// TradeEntry returns the entry of a trade.
+func TradeEntry(trade wex.TradeHistoryItem) Entry {
+	return Entry{Kind: KindTrade, ID: trade.ID, Time: trade.Timestamp, Trade: &trade}
+}
+
+// TransactionEntry returns the entry of a transaction.
+func TransactionEntry(transaction wex.TransactionHistoryItem) Entry {
+	return Entry{Kind: KindTransaction, ID: transaction.ID, Time: transaction.Timestamp, Transaction: &transaction}
+}
+
+type entryKey struct {
+	kind Kind
+	id   int
+}
+
+// Change is a change of the balance of a currency caused by an entry.
+type Change struct {
+	Currency wex.Currency
+	Amount   wex.Decimal
+}
+
+// Changes returns the balance changes of the entry, given fees in percent per pair.
+// A trade changes the balances of both currencies of the pair, with the fee deducted from the currency received.
+// Only successful transactions change balances. Deposits and credits add to the balance, withdrawals and debits subtract from it.
+func (e Entry) Changes(fees map[wex.Pair]wex.Decimal) []Change {
+	switch {
+	case e.Trade != nil:
+		trade := e.Trade
+		pair := wex.Pair(strings.ToLower(string(trade.Pair)))
+		keep := wex.NewDecimalFromInt(1).Sub(fees[pair].Div(wex.NewDecimalFromInt(100)))
+		total := trade.Amount.Mul(trade.Rate)
+		if trade.Type == wex.Sell {
+			return []Change{
+				{Currency: pair.Base(), Amount: trade.Amount.Neg()},
+				{Currency: pair.Quote(), Amount: total.Mul(keep)},
+			}
+		}
+		return []Change{
+			{Currency: pair.Base(), Amount: trade.Amount.Mul(keep)},
+			{Currency: pair.Quote(), Amount: total.Neg()},
+		}
+
+	case e.Transaction != nil:
+		transaction := e.Transaction
+		if transaction.Status != wex.TransactionSuccessful {
+			return nil
+		}
+		currency := wex.Currency(strings.ToLower(string(transaction.Currency)))
+		switch transaction.Type {
+		case wex.TransactionDeposit, wex.TransactionCredit:
+			return []Change{{Currency: currency, Amount: transaction.Amount}}
+		case wex.TransactionWithdrawal, wex.TransactionDebit:
+			return []Change{{Currency: currency, Amount: transaction.Amount.Neg()}}
+		}
+	}
+	return nil
+}
+
+// Ledger is a de-duplicated collection of trades and transactions of an account, persisted in a Store.
+// Its methods are safe for concurrent use.
+type Ledger struct {
+	// Fees are the fees of pairs in percent, e.g. from Info.Fees, deducted from amounts received by trades.
+	Fees map[wex.Pair]wex.Decimal
+	// Opening are the balances before the first entry, e.g. for accounts with history older than the ledger.
+	Opening map[wex.Currency]wex.Decimal
+	// Exclude skips entries when balances are reconstructed, e.g. transactions which duplicate trades.
+	Exclude func(entry Entry) bool
+
+	store   Store
+	mu      sync.RWMutex
+	entries map[entryKey]Entry
+}
+
+// Open returns a Ledger with the entries loaded from store.
+func Open(store Store) (*Ledger, error) {
+	loaded, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Ledger{store: store, entries: make(map[entryKey]Entry, len(loaded))}
+	for _, entry := range loaded {
+		l.entries[entryKey{entry.Kind, entry.ID}] = entry
+	}
+	return l, nil
+}
+
+// Add stores the entries which are not in the ledger yet and returns the number of entries added.
+func (l *Ledger) Add(entries ...Entry) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var added []Entry
+	for _, entry := range entries {
+		key := entryKey{entry.Kind, entry.ID}
+		if _, ok := l.entries[key]; ok {
+			continue
+		}
+		l.entries[key] = entry
+		added = append(added, entry)
+	}
+	if err := l.store.Append(added...); err != nil {
+		for _, entry := range added {
+			delete(l.entries, entryKey{entry.Kind, entry.ID})
+		}
+		return 0, err
+	}
+	return len(added), nil
+}
+
+// Cursor returns the position after the last entry of the kind, to continue fetching history from.
+func (l *Ledger) Cursor(kind Kind) wex.HistoryCursor {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	cursor := wex.HistoryCursor{}
+	for key := range l.entries {
+		if key.kind == kind && key.id > cursor.LastID {
+			cursor.LastID = key.id
+		}
+	}
+	return cursor
+}
+
+// syncBatch is the number of entries fetched before they are stored, so that an interrupted Sync keeps its progress.
+const syncBatch = 1000
+
+// Sync fetches the transactions and trades after the last ones in the ledger and stores them.
+// It returns the number of entries added, including those added before an error.
+func (l *Ledger) Sync(ctx context.Context, tapi *wex.TradeAPI) (int, error) {
+	total := 0
+	flush := func(batch []Entry) error {
+		added, err := l.Add(batch...)
+		total += added
+		return err
+	}
+
+	var batch []Entry
+	transactions := tapi.IterateTransactionHistory(wex.HistoryFilter{}.After(l.Cursor(KindTransaction)))
+	for transactions.Next(ctx) {
+		batch = append(batch, TransactionEntry(transactions.Item()))
+		if len(batch) == syncBatch {
+			if err := flush(batch); err != nil {
+				return total, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := flush(batch); err != nil {
+		return total, err
+	}
+	if err := transactions.Err(); err != nil {
+		return total, err
+	}
+
+	batch = batch[:0]
+	trades := tapi.IterateTradeHistory(wex.HistoryFilter{}.After(l.Cursor(KindTrade)), "")
+	for trades.Next(ctx) {
+		batch = append(batch, TradeEntry(trades.Item()))
+		if len(batch) == syncBatch {
+			if err := flush(batch); err != nil {
+				return total, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := flush(batch); err != nil {
+		return total, err
+	}
+	return total, trades.Err()
+}
+
+// Entries returns the entries in chronological order, with transactions before trades of the same second.
+func (l *Ledger) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]Entry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.Time.Equal(b.Time.Time) {
+			return a.Time.Before(b.Time.Time)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind == KindTransaction
+		}
+		return a.ID < b.ID
+	})
+	return entries
+}
+
+// Point is the balance of a currency after an entry.
+type Point struct {
+	Time    time.Time
+	Kind    Kind
+	ID      int
+	Change  wex.Decimal
+	Balance wex.Decimal
+}
+
+// Timeline returns the balance of the currency after each entry changing it, in chronological order.
+func (l *Ledger) Timeline(currency wex.Currency) []Point {
+	currency = wex.Currency(strings.ToLower(string(currency)))
+	balance := l.Opening[currency]
+
+	var points []Point
+	for _, entry := range l.Entries() {
+		if l.Exclude != nil && l.Exclude(entry) {
+			continue
+		}
+		for _, change := range entry.Changes(l.Fees) {
+			if change.Currency != currency {
+				continue
+			}
+			balance = balance.Add(change.Amount)
+			points = append(points, Point{Time: entry.Time.Time, Kind: entry.Kind, ID: entry.ID, Change: change.Amount, Balance: balance})
+		}
+	}
+	return points
+}
+
+// Balances returns the balances of all currencies after the entries up to at, or after all entries if at is zero.
+func (l *Ledger) Balances(at time.Time) map[wex.Currency]wex.Decimal {
+	balances := make(map[wex.Currency]wex.Decimal, len(l.Opening))
+	for currency, amount := range l.Opening {
+		balances[wex.Currency(strings.ToLower(string(currency)))] = amount
+	}
+
+	for _, entry := range l.Entries() {
+		if !at.IsZero() && entry.Time.After(at) {
+			break
+		}
+		if l.Exclude != nil && l.Exclude(entry) {
+			continue
+		}
+		for _, change := range entry.Changes(l.Fees) {
+			balances[change.Currency] = balances[change.Currency].Add(change.Amount)
+		}
+	}
+	return balances
+}
+
+// Discrepancy is a difference between the balance reconstructed by the ledger and the actual funds of a currency.
+type Discrepancy struct {
+	Currency   wex.Currency
+	Expected   wex.Decimal
+	Actual     wex.Decimal
+	Difference wex.Decimal
+}
+
+// Reconcile compares the reconstructed balances with funds and returns the currencies whose balances differ by more than tolerance, sorted by currency.
+func (l *Ledger) Reconcile(funds map[wex.Currency]wex.Decimal, tolerance wex.Decimal) []Discrepancy {
+	expected := l.Balances(time.Time{})
+	actual := make(map[wex.Currency]wex.Decimal, len(funds))
+	for currency, amount := range funds {
+		actual[wex.Currency(strings.ToLower(string(currency)))] = amount
+	}
+
+	currencies := make(map[wex.Currency]bool, len(expected)+len(actual))
+	for currency := range expected {
+		currencies[currency] = true
+	}
+	for currency := range actual {
+		currencies[currency] = true
+	}
+
+	var discrepancies []Discrepancy
+	for currency := range currencies {
+		difference := actual[currency].Sub(expected[currency])
+		if difference.Abs().Cmp(tolerance) > 0 {
+			discrepancies = append(discrepancies, Discrepancy{Currency: currency, Expected: expected[currency], Actual: actual[currency], Difference: difference})
+		}
+	}
+	sort.Slice(discrepancies, func(i, j int) bool { return discrepancies[i].Currency < discrepancies[j].Currency })
+	return discrepancies
+}
+
+// Check compares the reconstructed balances with the funds returned by GetInfo.
+// Funds in open orders are not included in GetInfo funds, so balances match only without active orders.
+func (l *Ledger) Check(ctx context.Context, tapi *wex.TradeAPI, tolerance wex.Decimal) ([]Discrepancy, error) {
+	info, err := tapi.GetInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return l.Reconcile(info.Funds, tolerance), nil
+}
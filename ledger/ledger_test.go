@@ -0,0 +1,128 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// tradeServer serves the transactions and trades of histories with IDs from from_id on.
+func tradeServer(transactions map[int]string, trades map[int]string, funds string) *httptest.Server {
+	var mu sync.Mutex
+	page := func(items map[int]string, from int, empty string) string {
+		var parts []string
+		for id := from; id <= 100; id++ {
+			if item, ok := items[id]; ok {
+				parts = append(parts, fmt.Sprintf(`"%d":%s`, id, item))
+			}
+		}
+		if len(parts) == 0 {
+			return `{"success":0,"error":"` + empty + `"}`
+		}
+		return `{"success":1,"return":{` + strings.Join(parts, ",") + `}}`
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		values, _ := url.ParseQuery(string(body))
+		from, _ := strconv.Atoi(values.Get("from_id"))
+		switch values.Get("method") {
+		case "TransHistory":
+			w.Write([]byte(page(transactions, from, "no transactions")))
+		case "TradeHistory":
+			w.Write([]byte(page(trades, from, "no trades")))
+		case "getInfo":
+			w.Write([]byte(`{"success":1,"return":{"funds":` + funds + `}}`))
+		}
+	}))
+}
+
+func TestLedger(t *testing.T) {
+
+	Convey("Ledger synchronized with an account", t, func() {
+
+		transactions := map[int]string{
+			1: `{"type":1,"amount":1000,"currency":"USD","status":2,"timestamp":1500000000}`,
+			2: `{"type":1,"amount":5,"currency":"BTC","status":1,"timestamp":1500000100}`,
+			3: `{"type":2,"amount":100,"currency":"USD","status":2,"timestamp":1500000300}`,
+		}
+		trades := map[int]string{
+			10: `{"pair":"btc_usd","type":"buy","amount":1,"rate":500,"order_id":1,"timestamp":1500000200}`,
+			11: `{"pair":"btc_usd","type":"sell","amount":0.5,"rate":600,"order_id":2,"timestamp":1500000400}`,
+		}
+		server := tradeServer(transactions, trades, `{"usd":500,"btc":0.5}`)
+		defer server.Close()
+		tapi := wex.NewTradeAPI("key", "secret", wex.WithTradeURL(server.URL))
+
+		store := &MemoryStore{}
+		book, err := Open(store)
+		So(err, ShouldBeNil)
+		book.Fees = map[wex.Pair]wex.Decimal{"btc_usd": wex.MustParseDecimal("0.2")}
+
+		added, err := book.Sync(context.Background(), tapi)
+		So(err, ShouldBeNil)
+		So(added, ShouldEqual, 5)
+
+		Convey("Entries should be persisted and ordered chronologically", func() {
+			entries := book.Entries()
+			So(len(entries), ShouldEqual, 5)
+			So(entries[2].Kind, ShouldEqual, KindTrade)
+			So(entries[2].ID, ShouldEqual, 10)
+
+			reopened, err := Open(store)
+			So(err, ShouldBeNil)
+			So(len(reopened.Entries()), ShouldEqual, 5)
+			So(reopened.Cursor(KindTrade).LastID, ShouldEqual, 11)
+		})
+
+		Convey("Sync should only add new entries", func() {
+			added, err := book.Sync(context.Background(), tapi)
+			So(err, ShouldBeNil)
+			So(added, ShouldEqual, 0)
+
+			added, err = book.Add(TradeEntry(wex.TradeHistoryItem{ID: 10}), TradeEntry(wex.TradeHistoryItem{ID: 12}))
+			So(err, ShouldBeNil)
+			So(added, ShouldEqual, 1)
+		})
+
+		Convey("Balances should be reconstructed with fees", func() {
+			timeline := book.Timeline("USD")
+			So(len(timeline), ShouldEqual, 4)
+			So(timeline[1].Change.String(), ShouldEqual, "-500")
+			So(timeline[3].Balance.String(), ShouldEqual, "699.4")
+
+			btc := book.Timeline("btc")
+			So(btc[0].Balance.String(), ShouldEqual, "0.998")
+			So(btc[1].Balance.String(), ShouldEqual, "0.498")
+
+			balances := book.Balances(time.Unix(1500000250, 0))
+			So(balances["usd"].String(), ShouldEqual, "500")
+			So(balances["btc"].String(), ShouldEqual, "0.998")
+		})
+
+		Convey("Discrepancies against funds should be flagged", func() {
+			discrepancies, err := book.Check(context.Background(), tapi, wex.NewDecimal(1, -8))
+			So(err, ShouldBeNil)
+			So(len(discrepancies), ShouldEqual, 2)
+			So(discrepancies[0].Currency, ShouldEqual, wex.Currency("btc"))
+			So(discrepancies[0].Difference.String(), ShouldEqual, "0.002")
+			So(discrepancies[1].Currency, ShouldEqual, wex.Currency("usd"))
+			So(discrepancies[1].Expected.String(), ShouldEqual, "699.4")
+
+			book.Opening = map[wex.Currency]wex.Decimal{"btc": wex.MustParseDecimal("0.002")}
+			So(book.Reconcile(map[wex.Currency]wex.Decimal{"btc": wex.MustParseDecimal("0.5"), "usd": wex.MustParseDecimal("699.4")}, wex.Decimal{}), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// Store persists ledger entries.
+type Store interface {
+	// Load returns all entries appended to the store.
+	Load() ([]Entry, error)
+	// Append adds entries to the store.
+	Append(entries ...Entry) error
+}
+
+// FileStore stores entries in a file as JSON lines, one entry per line.
+// Entries are only appended, so the file can be inspected and processed with line based tools.
+type FileStore struct {
+	Path string
+}
+
+// NewFileStore returns a FileStore keeping entries in the file at path, which is created when entries are first appended.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// Load reads the entries of the file. A missing file has no entries.
+// A last line without line break, left by an interrupted Append, is ignored.
+func (s *FileStore) Load() ([]Entry, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i < len(data)-1 {
+		data = data[:i+1]
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("ledger: %v line %d: %v", s.Path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Append writes the entries at the end of the file and syncs it to disk.
+// A last line without line break, left by an interrupted Append, is truncated first, so that the entries start on a line of their own.
+func (s *FileStore) Append(entries ...Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(s.Path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	end, err := completeLength(file)
+	if err == nil {
+		err = file.Truncate(end)
+	}
+	if err == nil {
+		_, err = file.WriteAt(buf.Bytes(), end)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// completeLength returns the length of the file up to and including its last line break.
+func completeLength(file *os.File) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	chunk := make([]byte, 4096)
+	for end := info.Size(); end > 0; {
+		start := end - int64(len(chunk))
+		if start < 0 {
+			start = 0
+		}
+		n, err := file.ReadAt(chunk[:end-start], start)
+		if err != nil && n < int(end-start) {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk[:n], '\n'); i >= 0 {
+			return start + int64(i) + 1, nil
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// MemoryStore keeps entries in memory, e.g. for tests or one-off reconstructions.
+type MemoryStore struct {
+	entries []Entry
+}
+
+// Load returns the appended entries.
+func (s *MemoryStore) Load() ([]Entry, error) {
+	return append([]Entry(nil), s.entries...), nil
+}
+
+// Append adds the entries.
+func (s *MemoryStore) Append(entries ...Entry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
@@ -0,0 +1,95 @@
+package ledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	wex "github.com/onuryilmaz/go-wex"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileStore(t *testing.T) {
+
+	Convey("File store in a temporary directory", t, func() {
+
+		dir, err := ioutil.TempDir("", "ledger")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		store := NewFileStore(filepath.Join(dir, "ledger.jsonl"))
+
+		Convey("Missing file should have no entries", func() {
+			entries, err := store.Load()
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+
+		Convey("Appended entries should be loaded", func() {
+			So(store.Append(TradeEntry(wex.TradeHistoryItem{ID: 1, Pair: "btc_usd", Type: wex.Buy, Amount: wex.NewDecimalFromInt(1)})), ShouldBeNil)
+			So(store.Append(TransactionEntry(wex.TransactionHistoryItem{ID: 2, Type: wex.TransactionDeposit, Currency: "USD"})), ShouldBeNil)
+
+			entries, err := store.Load()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 2)
+			So(entries[0].Trade.Amount.String(), ShouldEqual, "1")
+			So(entries[0].Trade.ID, ShouldEqual, 1)
+			So(entries[1].Kind, ShouldEqual, KindTransaction)
+			So(entries[1].Transaction.Type, ShouldEqual, wex.TransactionDeposit)
+			So(entries[1].Transaction.ID, ShouldEqual, 2)
+		})
+
+		Convey("Interrupted last line should be ignored", func() {
+			So(store.Append(TradeEntry(wex.TradeHistoryItem{ID: 1})), ShouldBeNil)
+			file, _ := os.OpenFile(store.Path, os.O_WRONLY|os.O_APPEND, 0600)
+			file.Write([]byte(`{"kind":"trade","id":2,"ti`))
+			file.Close()
+
+			entries, err := store.Load()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 1)
+		})
+
+		Convey("Entries appended after an interrupted line should be loaded", func() {
+			So(store.Append(TradeEntry(wex.TradeHistoryItem{ID: 1})), ShouldBeNil)
+			file, _ := os.OpenFile(store.Path, os.O_WRONLY|os.O_APPEND, 0600)
+			file.Write([]byte(`{"kind":"trade","id":2,"ti`))
+			file.Close()
+			So(store.Append(TradeEntry(wex.TradeHistoryItem{ID: 3})), ShouldBeNil)
+
+			book, err := Open(store)
+			So(err, ShouldBeNil)
+			entries := book.Entries()
+			So(len(entries), ShouldEqual, 2)
+			So(entries[0].ID, ShouldEqual, 1)
+			So(entries[1].ID, ShouldEqual, 3)
+		})
+
+		Convey("Interrupted first line should be truncated", func() {
+			So(ioutil.WriteFile(store.Path, []byte(`{"kind":"tr`), 0600), ShouldBeNil)
+			So(store.Append(TradeEntry(wex.TradeHistoryItem{ID: 1})), ShouldBeNil)
+
+			entries, err := store.Load()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].ID, ShouldEqual, 1)
+		})
+
+		Convey("Entries with unknown enum values should be reopened", func() {
+			So(store.Append(TransactionEntry(wex.TransactionHistoryItem{ID: 1, Type: wex.TransactionType(3), Status: wex.TransactionStatus(9), Currency: "USD"})), ShouldBeNil)
+
+			book, err := Open(store)
+			So(err, ShouldBeNil)
+			entries := book.Entries()
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Transaction.Type, ShouldEqual, wex.TransactionType(3))
+			So(entries[0].Transaction.Status, ShouldEqual, wex.TransactionStatus(9))
+		})
+
+		Convey("Malformed lines should be reported", func() {
+			So(ioutil.WriteFile(store.Path, []byte("{}\nnot json\n"), 0600), ShouldBeNil)
+			_, err := store.Load()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package wex
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// NonceSource provides nonces for Trade API requests. Each nonce must be greater than the nonce of the previous request sent with the same API key.
+type NonceSource interface {
+	// Next returns the nonce for the next request.
+	Next() (int64, error)
+	// Sync is called with the last nonce accepted by the server when a request is rejected because of an invalid nonce, so that next nonces are greater than it.
+	Sync(last int64) error
+}
+
+// WithNonceSource sets the source of nonces used by Trade API instead of the in-memory default.
+func WithNonceSource(source NonceSource) Option {
+	return func(c *Client) {
+		c.NonceSource = source
+	}
+}
+
+// nextNonce returns the nonce for the next request, which is the current Unix time unless it is not greater than last.
+func nextNonce(last int64) int64 {
+	nonce := time.Now().Unix()
+	if nonce <= last {
+		nonce = last + 1
+	}
+	return nonce
+}
+
+var invalidNonceKey = regexp.MustCompile(`on key:\s*(\d+)`)
+
+// invalidNonce returns the last nonce accepted by the server if err is an invalid nonce error, e.g. "invalid nonce parameter; on key:4000000, you sent:'1500', you should send:4000001".
+func invalidNonce(err error) (int64, bool) {
+	tradeErr, ok := err.(TradeError)
+	if !ok || !tradeErr.Is(ErrInvalidNonce) {
+		return 0, false
+	}
+	match := invalidNonceKey.FindStringSubmatch(tradeErr.Message)
+	if match == nil {
+		return 0, false
+	}
+	last, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return last, true
+}
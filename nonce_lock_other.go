@@ -0,0 +1,38 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package wex
+
+import (
+	"os"
+	"time"
+)
+
+// staleLockAge is the age after which a lock file is considered to be left behind by a crashed process.
+// Locks are held only while the nonce file is read and replaced, which takes far less.
+const staleLockAge = 30 * time.Second
+
+// lockFile acquires an exclusive lock by creating the file at path and returns the function releasing it by removing the file.
+// A lock file older than staleLockAge, left behind by a crashed process, is removed.
+func lockFile(path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// syncDir does nothing, as directories cannot be synced on all of these platforms.
+func syncDir(path string) error {
+	return nil
+}
@@ -0,0 +1,114 @@
+package wex
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NonceStore keeps the last nonce used with an API key, so that it can be shared by several processes and survive restarts.
+type NonceStore interface {
+	// Update atomically replaces the stored nonce with the result of fn called with the stored nonce, and returns the new nonce.
+	// The stored nonce is zero if nothing is stored yet.
+	Update(fn func(last int64) int64) (int64, error)
+}
+
+// StoreNonceSource returns a NonceSource keeping the last nonce in store.
+func StoreNonceSource(store NonceStore) NonceSource {
+	return storeNonceSource{store}
+}
+
+// WithNonceStore sets the store of the last nonce used by Trade API.
+func WithNonceStore(store NonceStore) Option {
+	return WithNonceSource(StoreNonceSource(store))
+}
+
+type storeNonceSource struct {
+	store NonceStore
+}
+
+func (s storeNonceSource) Next() (int64, error) {
+	return s.store.Update(nextNonce)
+}
+
+func (s storeNonceSource) Sync(last int64) error {
+	_, err := s.store.Update(func(stored int64) int64 {
+		if last > stored {
+			return last
+		}
+		return stored
+	})
+	return err
+}
+
+// FileNonceStore is a NonceStore keeping the last nonce in a file.
+// Updates are serialized with a lock on a separate ".lock" file and the nonce file is replaced atomically, so that the store can be shared by several processes on the same host.
+type FileNonceStore struct {
+	Path string
+}
+
+// NewFileNonceStore creates a FileNonceStore keeping the last nonce in the file at path.
+func NewFileNonceStore(path string) *FileNonceStore {
+	return &FileNonceStore{Path: path}
+}
+
+// Update implements NonceStore.
+func (s *FileNonceStore) Update(fn func(last int64) int64) (int64, error) {
+	unlock, err := lockFile(s.Path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	last, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+
+	nonce := fn(last)
+	if nonce == last {
+		return nonce, nil
+	}
+	return nonce, s.write(nonce)
+}
+
+func (s *FileNonceStore) read() (int64, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(content, 10, 64)
+}
+
+// write replaces the nonce file with a new file, so that readers never see a partially written nonce, and syncs the directory so that the replacement survives a crash.
+func (s *FileNonceStore) write(nonce int64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(strconv.FormatInt(nonce, 10)); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), s.Path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(s.Path))
+}
@@ -0,0 +1,69 @@
+package wex
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileNonceStore(t *testing.T) {
+
+	Convey("File nonce store", t, func() {
+
+		dir, err := ioutil.TempDir("", "wex-nonce")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "nonce")
+
+		Convey("Nonces should survive a restart", func() {
+			first, err := StoreNonceSource(NewFileNonceStore(path)).Next()
+			So(err, ShouldBeNil)
+
+			second, err := StoreNonceSource(NewFileNonceStore(path)).Next()
+			So(err, ShouldBeNil)
+			So(second, ShouldBeGreaterThan, first)
+		})
+
+		Convey("Sync should only move the stored nonce forward", func() {
+			source := StoreNonceSource(NewFileNonceStore(path))
+			So(source.Sync(1<<40), ShouldBeNil)
+			So(source.Sync(5), ShouldBeNil)
+
+			nonce, err := source.Next()
+			So(err, ShouldBeNil)
+			So(nonce, ShouldEqual, 1<<40+1)
+		})
+
+		Convey("Stores sharing the file should never return the same nonce", func() {
+			const workers, requests = 8, 20
+
+			var mu sync.Mutex
+			seen := make(map[int64]bool)
+			var wg sync.WaitGroup
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					source := StoreNonceSource(NewFileNonceStore(path))
+					for j := 0; j < requests; j++ {
+						nonce, err := source.Next()
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						mu.Lock()
+						seen[nonce] = true
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			So(seen, ShouldHaveLength, workers*requests)
+		})
+	})
+}
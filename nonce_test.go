@@ -0,0 +1,80 @@
+package wex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type counterNonce struct {
+	last   int64
+	synced []int64
+}
+
+func (n *counterNonce) Next() (int64, error) {
+	n.last++
+	return n.last, nil
+}
+
+func (n *counterNonce) Sync(last int64) error {
+	n.synced = append(n.synced, last)
+	n.last = last
+	return nil
+}
+
+// nonceServer accepts only nonces greater than the last accepted one, like the exchange does.
+func nonceServer(last int64) (*httptest.Server, *[]int64) {
+	var sent []int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce, _ := strconv.ParseInt(r.FormValue("nonce"), 10, 64)
+		sent = append(sent, nonce)
+		if nonce <= last {
+			fmt.Fprintf(w, `{"success":0,"error":"invalid nonce parameter; on key:%d, you sent:'%d', you should send:%d"}`, last, nonce, last+1)
+			return
+		}
+		last = nonce
+		w.Write([]byte(`{"success":1,"return":{"open_orders":1}}`))
+	}))
+	return server, &sent
+}
+
+func TestNonceRecovery(t *testing.T) {
+
+	Convey("Server that has seen a greater nonce", t, func() {
+
+		const serverNonce = 1 << 40
+		server, sent := nonceServer(serverNonce)
+		defer server.Close()
+
+		Convey("Default nonce should be resynchronized and the request retried", func() {
+			tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+			info, err := tapi.GetInfo()
+			So(err, ShouldBeNil)
+			So(info.OpenOrders, ShouldEqual, 1)
+			So(*sent, ShouldHaveLength, 2)
+			So((*sent)[1], ShouldEqual, serverNonce+1)
+		})
+
+		Convey("Custom nonce source should be used and synchronized", func() {
+			source := &counterNonce{}
+			tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL), WithNonceSource(source))
+			_, err := tapi.GetInfo()
+			So(err, ShouldBeNil)
+			So(*sent, ShouldResemble, []int64{1, serverNonce + 1})
+			So(source.synced, ShouldResemble, []int64{serverNonce})
+		})
+	})
+
+	Convey("Invalid nonce errors should be parsed", t, func() {
+		last, ok := invalidNonce(TradeError{Message: "invalid nonce parameter; on key:4000000, you sent:'1500', you should send:4000001"})
+		So(ok, ShouldBeTrue)
+		So(last, ShouldEqual, 4000000)
+
+		_, ok = invalidNonce(TradeError{Message: "no orders"})
+		So(ok, ShouldBeFalse)
+	})
+}
@@ -0,0 +1,216 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Order describes a limit order placed with TradeOrder or PlaceOrder.
+type Order struct {
+	Pair   Pair
+	Type   OrderType
+	Rate   Decimal
+	Amount Decimal
+}
+
+// ErrOrderNotPlaced is the class of errors returned by PlaceOrder when it is certain that the order was not created.
+var ErrOrderNotPlaced = errors.New("order not placed")
+
+// ErrOrderStateUnknown is the class of errors returned by PlaceOrder when it cannot be determined whether the order was created.
+var ErrOrderStateUnknown = errors.New("order state unknown")
+
+// OrderNotPlacedError is returned by PlaceOrder when the order was not created. Err is the error of the Trade request.
+type OrderNotPlacedError struct {
+	Err error
+}
+
+func (e OrderNotPlacedError) Error() string {
+	return fmt.Sprintf("order not placed: %v", e.Err)
+}
+
+// Unwrap returns the error of the Trade request.
+func (e OrderNotPlacedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrOrderNotPlaced.
+func (e OrderNotPlacedError) Is(target error) bool {
+	return target == ErrOrderNotPlaced
+}
+
+// OrderStateUnknownError is returned by PlaceOrder when the Trade request failed ambiguously and the reconciliation failed as well.
+// The order must not be placed again before its state is known.
+type OrderStateUnknownError struct {
+	Err          error
+	ReconcileErr error
+}
+
+func (e OrderStateUnknownError) Error() string {
+	return fmt.Sprintf("order state unknown: %v (reconciliation: %v)", e.Err, e.ReconcileErr)
+}
+
+// Unwrap returns the error of the Trade request.
+func (e OrderStateUnknownError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrOrderStateUnknown.
+func (e OrderStateUnknownError) Is(target error) bool {
+	return target == ErrOrderStateUnknown
+}
+
+// reconcileTimeout bounds the reconciliation requests when the context of PlaceOrder is already done.
+const reconcileTimeout = 30 * time.Second
+
+// reconcileWindow is how long PlaceOrder keeps looking for the order after an ambiguous failure, as a Trade request may still be processed by the exchange, and reconcileInterval the pause between attempts.
+var (
+	reconcileWindow   = 15 * time.Second
+	reconcileInterval = time.Second
+)
+
+// PlaceOrder creates an order like Trade, but never reports an order as not placed when it may have been created.
+//
+// Before the Trade request, the IDs of the active orders and the last trade of the pair are recorded.
+// If the Trade request then fails after it was sent but without a response from the exchange, e.g. because of a timeout, the active orders and trade history of the pair are queried repeatedly for a newer order matching pair, type, rate and amount.
+// The matching order is returned as if the Trade request had succeeded.
+// These queries carry newer nonces than the Trade request, which the exchange rejects once a newer nonce was accepted, so an order not found within reconcileWindow was not placed.
+//
+// The error is an OrderNotPlacedError when the exchange rejected the order, the request was not sent or the order was not found.
+// If the reconciliation fails or is stopped by the context, the error is an OrderStateUnknownError and the order must not be placed again before its state is known.
+func (tapi *TradeAPI) PlaceOrder(order Order) (TradeResponse, error) {
+	return tapi.PlaceOrderContext(context.Background(), order)
+}
+
+// PlaceOrderContext provides PlaceOrder capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) PlaceOrderContext(ctx context.Context, order Order) (TradeResponse, error) {
+
+	order, err := tapi.validateOrder(ctx, order)
+	if err != nil {
+		return TradeResponse{}, OrderNotPlacedError{err}
+	}
+
+	baseline, err := tapi.recordBaseline(ctx, order.Pair)
+	if err != nil {
+		return TradeResponse{}, OrderNotPlacedError{err}
+	}
+
+	response, err := tapi.trade(ctx, order)
+	if err == nil {
+		return response, nil
+	}
+	if !isAmbiguous(err) {
+		return response, OrderNotPlacedError{err}
+	}
+
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), reconcileTimeout)
+		defer cancel()
+	}
+
+	deadline := time.Now().Add(reconcileWindow)
+	for {
+		response, found, reconcileErr := tapi.reconcile(ctx, order, baseline)
+		if reconcileErr != nil {
+			return TradeResponse{}, OrderStateUnknownError{Err: err, ReconcileErr: reconcileErr}
+		}
+		if found {
+			return response, nil
+		}
+		if !time.Now().Add(reconcileInterval).Before(deadline) {
+			return TradeResponse{}, OrderNotPlacedError{err}
+		}
+
+		timer := time.NewTimer(reconcileInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return TradeResponse{}, OrderStateUnknownError{Err: err, ReconcileErr: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// isAmbiguous reports whether the Trade request may have created the order although it failed.
+// Errors reported by the exchange and errors before the request was sent are not ambiguous.
+func isAmbiguous(err error) bool {
+	var tradeErr TradeError
+	var rateLimitErr RateLimitError
+	var notSentErr notSentError
+	return !errors.As(err, &tradeErr) && !errors.As(err, &rateLimitErr) && !errors.As(err, &notSentErr)
+}
+
+// orderBaseline is the state of a pair before a Trade request: the IDs of the active orders and the ID of the last trade.
+type orderBaseline struct {
+	active    map[int]bool
+	lastTrade int
+}
+
+// recordBaseline records the active orders and the last trade of the pair, so that only newer ones are matched by reconcile.
+func (tapi *TradeAPI) recordBaseline(ctx context.Context, pair Pair) (orderBaseline, error) {
+
+	baseline := orderBaseline{active: make(map[int]bool)}
+
+	activeOrders, err := tapi.ActiveOrdersContext(ctx, pair)
+	if err != nil && !errors.Is(err, ErrNoOrders) {
+		return baseline, err
+	}
+	for _, active := range activeOrders {
+		baseline.active[active.ID] = true
+	}
+
+	history, err := tapi.TradeHistoryContext(ctx, HistoryFilter{Count: 1, Order: "DESC"}, pair)
+	if err != nil && !errors.Is(err, ErrNoTrades) {
+		return baseline, err
+	}
+	for _, trade := range history {
+		if trade.ID > baseline.lastTrade {
+			baseline.lastTrade = trade.ID
+		}
+	}
+	return baseline, nil
+}
+
+// reconcile looks for an order matching order which is newer than the baseline, first among active orders and then in trade history.
+func (tapi *TradeAPI) reconcile(ctx context.Context, order Order, baseline orderBaseline) (TradeResponse, bool, error) {
+
+	activeOrders, err := tapi.ActiveOrdersContext(ctx, order.Pair)
+	if err != nil && !errors.Is(err, ErrNoOrders) {
+		return TradeResponse{}, false, err
+	}
+	for _, active := range activeOrders.Items() {
+		if !baseline.active[active.ID] && active.Pair == order.Pair && active.Type == order.Type &&
+			active.Rate.Equal(order.Rate) && active.Amount.Cmp(order.Amount) <= 0 {
+			return TradeResponse{OrderID: active.ID, Received: order.Amount.Sub(active.Amount), Remains: active.Amount}, true, nil
+		}
+	}
+
+	// an order executed immediately does not appear among active orders, only its trades do
+	history, err := tapi.TradeHistoryContext(ctx, HistoryFilter{FromID: baseline.lastTrade + 1, Order: "ASC"}, order.Pair)
+	if err != nil && !errors.Is(err, ErrNoTrades) {
+		return TradeResponse{}, false, err
+	}
+	executed := make(map[int]Decimal)
+	for _, trade := range history.Items() {
+		if trade.ID > baseline.lastTrade && !baseline.active[trade.OrderID] && trade.Pair == order.Pair && trade.Type == order.Type && withinRate(trade.Rate, order) {
+			executed[trade.OrderID] = executed[trade.OrderID].Add(trade.Amount)
+		}
+	}
+	for orderID, amount := range executed {
+		if amount.Equal(order.Amount) {
+			return TradeResponse{OrderID: orderID, Received: amount}, true, nil
+		}
+	}
+
+	return TradeResponse{}, false, nil
+}
+
+// withinRate reports whether a trade at rate can fill the order, i.e. whether the rate is at most the rate of a buy or at least the rate of a sell.
+func withinRate(rate Decimal, order Order) bool {
+	if order.Type == Sell {
+		return rate.Cmp(order.Rate) >= 0
+	}
+	return rate.Cmp(order.Rate) <= 0
+}
@@ -0,0 +1,176 @@
+package wex
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPlaceOrder(t *testing.T) {
+
+	window, interval := reconcileWindow, reconcileInterval
+	reconcileWindow, reconcileInterval = 500*time.Millisecond, 20*time.Millisecond
+	defer func() { reconcileWindow, reconcileInterval = window, interval }()
+
+	Convey("Placing an order", t, func() {
+
+		// responses before the Trade request and after the exchange processed it
+		tradeResponse := ""
+		baselineOrders := `{"success":0,"error":"no orders"}`
+		baselineHistory := `{"success":0,"error":"no trades"}`
+		activeOrders := `{"success":0,"error":"no orders"}`
+		tradeHistory := `{"success":0,"error":"no trades"}`
+		var trades int
+		var processed bool
+		var mu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			switch values.Get("method") {
+			case "Trade":
+				trades++
+				if tradeResponse == "" {
+					// the exchange is still processing the order when the client times out
+					mu.Unlock()
+					time.Sleep(200 * time.Millisecond)
+					mu.Lock()
+					processed = true
+					return
+				}
+				w.Write([]byte(tradeResponse))
+			case "ActiveOrders":
+				if processed {
+					w.Write([]byte(activeOrders))
+				} else {
+					w.Write([]byte(baselineOrders))
+				}
+			case "TradeHistory":
+				if processed {
+					w.Write([]byte(tradeHistory))
+				} else {
+					w.Write([]byte(baselineHistory))
+				}
+			}
+		}))
+		defer server.Close()
+
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL),
+			WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}))
+		order := Order{Pair: "btc_usd", Type: "buy", Rate: NewDecimalFromInt(900), Amount: MustParseDecimal("1.5")}
+		set := func(response *string, value string) {
+			mu.Lock()
+			*response = value
+			mu.Unlock()
+		}
+
+		Convey("Successful response should be returned as is", func() {
+			set(&tradeResponse, `{"success":1,"return":{"received":0,"remains":1.5,"order_id":42}}`)
+			response, err := tapi.PlaceOrder(order)
+			So(err, ShouldBeNil)
+			So(response.OrderID, ShouldEqual, 42)
+		})
+
+		Convey("Exchange error should be definitive", func() {
+			set(&tradeResponse, `{"success":0,"error":"It is not enough USD for purchase"}`)
+			_, err := tapi.PlaceOrder(order)
+			So(errors.Is(err, ErrOrderNotPlaced), ShouldBeTrue)
+			So(errors.Is(err, ErrInsufficientFunds), ShouldBeTrue)
+		})
+
+		Convey("Timed out order appearing among active orders should be returned", func() {
+			identical := `"7":{"pair":"btc_usd","type":"buy","amount":1.5,"rate":900,"timestamp_created":1500000000,"status":0}`
+			set(&baselineOrders, `{"success":1,"return":{`+identical+`}}`)
+			set(&activeOrders, `{"success":1,"return":{`+identical+`,
+				"43":{"pair":"btc_usd","type":"buy","amount":1,"rate":900,"timestamp_created":1500000000,"status":0}}}`)
+			response, err := tapi.PlaceOrder(order)
+			So(err, ShouldBeNil)
+			So(response.OrderID, ShouldEqual, 43)
+			So(response.Remains.String(), ShouldEqual, "1")
+			So(response.Received.String(), ShouldEqual, "0.5")
+			mu.Lock()
+			So(trades, ShouldEqual, 1)
+			mu.Unlock()
+		})
+
+		Convey("Timed out order executed immediately should be found in trade history", func() {
+			earlier := `"5":{"pair":"btc_usd","type":"buy","amount":1.5,"rate":900,"order_id":40,"is_your_order":0,"timestamp":1500000000}`
+			set(&baselineHistory, `{"success":1,"return":{`+earlier+`}}`)
+			set(&tradeHistory, `{"success":1,"return":{`+earlier+`,
+				"6":{"pair":"btc_usd","type":"buy","amount":1,"rate":899,"order_id":44,"is_your_order":0,"timestamp":1500000000},
+				"7":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":900,"order_id":44,"is_your_order":0,"timestamp":1500000000}}}`)
+			response, err := tapi.PlaceOrder(order)
+			So(err, ShouldBeNil)
+			So(response.OrderID, ShouldEqual, 44)
+			So(response.Received.String(), ShouldEqual, "1.5")
+		})
+
+		Convey("Timed out order not found should be reported as not placed", func() {
+			earlier := `"7":{"pair":"btc_usd","type":"buy","amount":1.5,"rate":900,"timestamp_created":1500000000,"status":0}`
+			set(&baselineOrders, `{"success":1,"return":{`+earlier+`}}`)
+			set(&activeOrders, `{"success":1,"return":{`+earlier+`}}`)
+			set(&tradeHistory, `{"success":1,"return":{
+				"6":{"pair":"btc_usd","type":"buy","amount":1.5,"rate":901,"order_id":44,"is_your_order":0,"timestamp":1500000000}}}`)
+			_, err := tapi.PlaceOrder(order)
+			So(errors.Is(err, ErrOrderNotPlaced), ShouldBeTrue)
+			So(errors.Is(err, ErrOrderStateUnknown), ShouldBeFalse)
+		})
+
+		Convey("Failing reconciliation should be reported as unknown state", func() {
+			set(&activeOrders, `{"success":0,"error":"api key dont have info permission"}`)
+			_, err := tapi.PlaceOrder(order)
+			So(errors.Is(err, ErrOrderStateUnknown), ShouldBeTrue)
+		})
+
+		Convey("Failure before sending should be reported as not placed without reconciliation", func() {
+			source := &failingNonceSource{failAfter: 2}
+			tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL), WithNonceSource(source))
+			start := time.Now()
+			_, err := tapi.PlaceOrder(order)
+			So(errors.Is(err, ErrOrderNotPlaced), ShouldBeTrue)
+			So(time.Since(start), ShouldBeLessThan, reconcileWindow)
+			mu.Lock()
+			So(trades, ShouldEqual, 0)
+			mu.Unlock()
+		})
+
+		Convey("Failing baseline should be reported as not placed without sending the order", func() {
+			set(&baselineOrders, `{"success":0,"error":"api key dont have info permission"}`)
+			_, err := tapi.PlaceOrder(order)
+			So(errors.Is(err, ErrOrderNotPlaced), ShouldBeTrue)
+			mu.Lock()
+			So(trades, ShouldEqual, 0)
+			mu.Unlock()
+		})
+	})
+}
+
+// failingNonceSource fails to allocate nonces after failAfter nonces.
+type failingNonceSource struct {
+	mu        sync.Mutex
+	last      int64
+	failAfter int
+}
+
+func (s *failingNonceSource) Next() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failAfter == 0 {
+		return 0, errors.New("nonce store unavailable")
+	}
+	s.failAfter--
+	s.last++
+	return s.last, nil
+}
+
+func (s *failingNonceSource) Sync(last int64) error {
+	return nil
+}
@@ -0,0 +1,244 @@
+package wex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BookSide is a side of the order book.
+type BookSide string
+
+// Sides of the order book.
+const (
+	Ask BookSide = "ask"
+	Bid BookSide = "bid"
+)
+
+// BookEventType is the kind of change of a price level.
+type BookEventType int
+
+// Kinds of changes of price levels.
+const (
+	LevelAdded BookEventType = iota
+	LevelUpdated
+	LevelRemoved
+)
+
+var bookEventTypeNames = map[int]string{
+	0: "added",
+	1: "updated",
+	2: "removed",
+}
+
+func (t BookEventType) String() string {
+	return enumName(int(t), bookEventTypeNames, "BookEventType")
+}
+
+// BookEvent is a change of a price level between two snapshots of the order book.
+// Amount is zero for removed levels and Previous is zero for added levels.
+type BookEvent struct {
+	Pair     Pair
+	Side     BookSide
+	Type     BookEventType
+	Price    Decimal
+	Amount   Decimal
+	Previous Decimal
+	Time     time.Time
+}
+
+// OrderBook maintains local order books of pairs by polling PublicAPI.Depth and publishes the changes of price levels to subscribers.
+// Its methods are safe for concurrent use.
+//
+// With a limit, only the best limit levels of each side are known. A level leaving this window is not reported as removed, as Depth does not tell
+// whether it was removed, and it is reported as added again when it returns to the window.
+type OrderBook struct {
+	// OnError is called with errors of Depth requests. Polling continues after errors.
+	OnError func(err error)
+
+	api      *PublicAPI
+	pairs    []Pair
+	interval time.Duration
+	limit    int
+
+	// delivery serializes polls from computing the changes until they are delivered, so that subscribers receive changes in order and channels are not closed during a delivery.
+	delivery    sync.Mutex
+	mu          sync.RWMutex
+	books       map[Pair]DepthPair
+	updated     map[Pair]time.Time
+	subscribers []chan BookEvent
+	closed      bool
+}
+
+// NewOrderBook creates an OrderBook polling Depth of the pairs with the given limit of levels every interval.
+func NewOrderBook(api *PublicAPI, pairs []Pair, interval time.Duration, limit int) *OrderBook {
+	return &OrderBook{
+		api:      api,
+		pairs:    pairs,
+		interval: interval,
+		limit:    limit,
+		books:    make(map[Pair]DepthPair, len(pairs)),
+		updated:  make(map[Pair]time.Time, len(pairs)),
+	}
+}
+
+// Subscribe returns a channel receiving changes of price levels, buffered by buffer events.
+// The first poll reports every level as added. The channel is closed when Run returns, or immediately if Run has returned already.
+// Slow subscribers delay polling, as events are delivered before the next poll.
+func (b *OrderBook) Subscribe(buffer int) <-chan BookEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan BookEvent, buffer)
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subscribers = append(b.subscribers, ch)
+	return ch
+}
+
+// Run polls the order books until ctx is done and returns the context error.
+func (b *OrderBook) Run(ctx context.Context) error {
+	defer b.closeSubscribers()
+	return poll(ctx, b.interval, b.Poll, b.OnError)
+}
+
+// Poll requests the order books once, updates the local books and delivers the changes to subscribers.
+// Poll may be called while Run is running. After Run has returned, Poll only updates the local books.
+func (b *OrderBook) Poll(ctx context.Context) error {
+	depth, err := b.api.DepthContext(ctx, b.pairs, b.limit)
+	if err != nil {
+		return err
+	}
+
+	b.delivery.Lock()
+	defer b.delivery.Unlock()
+
+	now := time.Now()
+	var events []BookEvent
+
+	b.mu.Lock()
+	for _, pair := range b.pairs {
+		snapshot, ok := depth[pair]
+		if !ok {
+			continue
+		}
+		previous := b.books[pair]
+		events = append(events, diffLevels(pair, Ask, previous.Asks, snapshot.Asks, b.limit, now)...)
+		events = append(events, diffLevels(pair, Bid, previous.Bids, snapshot.Bids, b.limit, now)...)
+		b.books[pair] = snapshot
+		b.updated[pair] = now
+	}
+	subscribers := append([]chan BookEvent(nil), b.subscribers...)
+	b.mu.Unlock()
+
+	for _, event := range events {
+		for _, ch := range subscribers {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// closeSubscribers closes the channels of the subscribers after a delivery in progress.
+func (b *OrderBook) closeSubscribers() {
+	b.delivery.Lock()
+	defer b.delivery.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for _, ch := range b.subscribers {
+		close(ch)
+	}
+	b.subscribers = nil
+}
+
+// Snapshot returns the last order book of the pair and the time it was received, or false if it was not received yet.
+func (b *OrderBook) Snapshot(pair Pair) (DepthPair, time.Time, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	book, ok := b.books[pair]
+	return book, b.updated[pair], ok
+}
+
+// BestAsk returns the ask with the lowest price of the pair, or false if there is none.
+func (b *OrderBook) BestAsk(pair Pair) (DepthItem, bool) {
+	book, _, _ := b.Snapshot(pair)
+	return book.BestAsk()
+}
+
+// BestBid returns the bid with the highest price of the pair, or false if there is none.
+func (b *OrderBook) BestBid(pair Pair) (DepthItem, bool) {
+	book, _, _ := b.Snapshot(pair)
+	return book.BestBid()
+}
+
+// TopAsks returns up to n asks of the pair with the lowest prices, in ascending order of price.
+func (b *OrderBook) TopAsks(pair Pair, n int) []DepthItem {
+	book, _, _ := b.Snapshot(pair)
+	return top(book.sortedAsks(), n)
+}
+
+// TopBids returns up to n bids of the pair with the highest prices, in descending order of price.
+func (b *OrderBook) TopBids(pair Pair, n int) []DepthItem {
+	book, _, _ := b.Snapshot(pair)
+	return top(book.sortedBids(), n)
+}
+
+func top(levels []DepthItem, n int) []DepthItem {
+	if n >= 0 && len(levels) > n {
+		return levels[:n]
+	}
+	return levels
+}
+
+// diffLevels returns the changes between previous and current levels of a side, ordered by price.
+// If current holds limit levels, previous levels worse than all of them left the window of the limit and are not reported as removed.
+func diffLevels(pair Pair, side BookSide, previous []DepthItem, current []DepthItem, limit int, now time.Time) []BookEvent {
+	old := make(map[string]DepthItem, len(previous))
+	for _, level := range previous {
+		old[level.Price.String()] = level
+	}
+
+	var events []BookEvent
+	for _, level := range current {
+		key := level.Price.String()
+		before, ok := old[key]
+		delete(old, key)
+		switch {
+		case !ok:
+			events = append(events, BookEvent{Pair: pair, Side: side, Type: LevelAdded, Price: level.Price, Amount: level.Amount, Time: now})
+		case !before.Amount.Equal(level.Amount):
+			events = append(events, BookEvent{Pair: pair, Side: side, Type: LevelUpdated, Price: level.Price, Amount: level.Amount, Previous: before.Amount, Time: now})
+		}
+	}
+	full := limit > 0 && len(current) >= limit
+	for _, level := range old {
+		if full && outsideWindow(side, level.Price, current) {
+			continue
+		}
+		events = append(events, BookEvent{Pair: pair, Side: side, Type: LevelRemoved, Price: level.Price, Previous: level.Amount, Time: now})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Price.Cmp(events[j].Price) < 0 })
+	return events
+}
+
+// outsideWindow reports whether price is worse than all levels of a side, i.e. above all asks or below all bids.
+func outsideWindow(side BookSide, price Decimal, levels []DepthItem) bool {
+	for _, level := range levels {
+		cmp := price.Cmp(level.Price)
+		if side == Ask && cmp <= 0 || side == Bid && cmp >= 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package wex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderBook(t *testing.T) {
+
+	Convey("Order book polling a server", t, func() {
+
+		var mu sync.Mutex
+		body := `{"btc_usd":{"asks":[[101,1],[102,2]],"bids":[[100,1],[99,2]]}}`
+		set := func(b string) {
+			mu.Lock()
+			defer mu.Unlock()
+			body = b
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		api := NewPublicAPI(WithPublicURL(server.URL))
+		book := NewOrderBook(api, []Pair{"btc_usd"}, time.Hour, 10)
+		events := book.Subscribe(16)
+
+		Convey("First poll should add all levels", func() {
+			So(book.Poll(context.Background()), ShouldBeNil)
+			So(len(events), ShouldEqual, 4)
+			event := <-events
+			So(event.Type, ShouldEqual, LevelAdded)
+			So(event.Pair, ShouldEqual, Pair("btc_usd"))
+
+			ask, ok := book.BestAsk("btc_usd")
+			So(ok, ShouldBeTrue)
+			So(ask.Price.String(), ShouldEqual, "101")
+			bids := book.TopBids("btc_usd", 1)
+			So(len(bids), ShouldEqual, 1)
+			So(bids[0].Price.String(), ShouldEqual, "100")
+		})
+
+		Convey("Next polls should report changed levels only", func() {
+			So(book.Poll(context.Background()), ShouldBeNil)
+			for len(events) > 0 {
+				<-events
+			}
+
+			set(`{"btc_usd":{"asks":[[101,1.5],[103,1]],"bids":[[100,1],[99,2]]}}`)
+			So(book.Poll(context.Background()), ShouldBeNil)
+			So(len(events), ShouldEqual, 3)
+
+			updated := <-events
+			So(updated.Type, ShouldEqual, LevelUpdated)
+			So(updated.Side, ShouldEqual, Ask)
+			So(updated.Amount.String(), ShouldEqual, "1.5")
+			So(updated.Previous.String(), ShouldEqual, "1")
+
+			removed := <-events
+			So(removed.Type, ShouldEqual, LevelRemoved)
+			So(removed.Price.String(), ShouldEqual, "102")
+			So(removed.Amount.IsZero(), ShouldBeTrue)
+
+			added := <-events
+			So(added.Type, ShouldEqual, LevelAdded)
+			So(added.Price.String(), ShouldEqual, "103")
+		})
+
+		Convey("Run should close subscriptions when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- book.Run(ctx) }()
+
+			<-events
+			cancel()
+			So(<-done, ShouldEqual, context.Canceled)
+			for range events {
+			}
+			_, _, ok := book.Snapshot("btc_usd")
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("Subscriptions after Run should be closed", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			So(book.Run(ctx), ShouldEqual, context.Canceled)
+
+			_, ok := <-book.Subscribe(1)
+			So(ok, ShouldBeFalse)
+			So(book.Poll(context.Background()), ShouldBeNil)
+		})
+
+		Convey("Levels leaving the limit should not be reported as removed", func() {
+			limited := NewOrderBook(api, []Pair{"btc_usd"}, time.Hour, 2)
+			limitedEvents := limited.Subscribe(16)
+			So(limited.Poll(context.Background()), ShouldBeNil)
+			for len(limitedEvents) > 0 {
+				<-limitedEvents
+			}
+
+			set(`{"btc_usd":{"asks":[[100.5,1],[101,1]],"bids":[[100,1]]}}`)
+			So(limited.Poll(context.Background()), ShouldBeNil)
+			So(len(limitedEvents), ShouldEqual, 2)
+
+			added := <-limitedEvents
+			So(added.Type, ShouldEqual, LevelAdded)
+			So(added.Price.String(), ShouldEqual, "100.5")
+			removed := <-limitedEvents
+			So(removed.Type, ShouldEqual, LevelRemoved)
+			So(removed.Side, ShouldEqual, Bid)
+			So(removed.Price.String(), ShouldEqual, "99")
+		})
+	})
+}
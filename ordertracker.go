@@ -0,0 +1,316 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OrderEventType is the kind of change of a tracked order.
+type OrderEventType int
+
+// Kinds of changes of tracked orders.
+const (
+	OrderEventPartiallyFilled OrderEventType = iota
+	OrderEventFilled
+	OrderEventCanceled
+)
+
+var orderEventTypeNames = map[int]string{
+	0: "partially filled",
+	1: "filled",
+	2: "canceled",
+}
+
+func (t OrderEventType) String() string {
+	return enumName(int(t), orderEventTypeNames, "OrderEventType")
+}
+
+// OrderEvent is a change of an order tracked by OrderTracker.
+// Delta is the amount filled since the previous event and Trades are the trades of the order filling it, with AveragePrice their volume-weighted rate.
+// Trades may be empty if they were not found in the trade history yet.
+type OrderEvent struct {
+	OrderID      int
+	Type         OrderEventType
+	Pair         Pair
+	OrderType    OrderType
+	Rate         Decimal
+	StartAmount  Decimal
+	Filled       Decimal
+	Remaining    Decimal
+	Delta        Decimal
+	Trades       []TradeHistoryItem
+	AveragePrice Decimal
+	Time         time.Time
+}
+
+type trackedOrder struct {
+	info   OrderInfoItem
+	known  bool
+	trades map[string]bool
+}
+
+// OrderTracker polls TradeAPI.ActiveOrders and TradeAPI.OrderInfo for tracked orders and reports fills and cancellations to subscribers.
+// Orders are no longer tracked after they were filled or canceled.
+// Its methods are safe for concurrent use.
+type OrderTracker struct {
+	// OnError is called with errors of requests. Polling continues after errors.
+	OnError func(err error)
+
+	tapi     *TradeAPI
+	interval time.Duration
+
+	mu          sync.Mutex
+	orders      map[int]*trackedOrder
+	subscribers []chan OrderEvent
+}
+
+// NewOrderTracker creates an OrderTracker polling tracked orders every interval.
+func NewOrderTracker(tapi *TradeAPI, interval time.Duration) *OrderTracker {
+	return &OrderTracker{
+		tapi:     tapi,
+		interval: interval,
+		orders:   make(map[int]*trackedOrder),
+	}
+}
+
+// Track starts tracking the order, e.g. TradeResponse.OrderID of a placed order.
+// Orders without ID, i.e. executed immediately when placed, cannot be tracked.
+func (t *OrderTracker) Track(orderID int) {
+	if orderID == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.orders[orderID]; !ok {
+		t.orders[orderID] = &trackedOrder{trades: make(map[string]bool)}
+	}
+}
+
+// Untrack stops tracking the order.
+func (t *OrderTracker) Untrack(orderID int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.orders, orderID)
+}
+
+// Tracked returns the IDs of tracked orders in ascending order.
+func (t *OrderTracker) Tracked() []int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ids := make([]int, 0, len(t.orders))
+	for id := range t.orders {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Subscribe returns a channel receiving order events, buffered by buffer events.
+// The channel is closed when Run returns.
+func (t *OrderTracker) Subscribe(buffer int) <-chan OrderEvent {
+	ch := make(chan OrderEvent, buffer)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.subscribers = append(t.subscribers, ch)
+	return ch
+}
+
+// Run polls the tracked orders until ctx is done and returns the context error.
+func (t *OrderTracker) Run(ctx context.Context) error {
+	defer t.closeSubscribers()
+	return poll(ctx, t.interval, t.Poll, t.OnError)
+}
+
+// Poll checks the tracked orders once and delivers their changes to subscribers.
+// Errors of OrderInfo requests of single orders are passed to OnError and the other orders are checked nevertheless.
+func (t *OrderTracker) Poll(ctx context.Context) error {
+	ids := t.Tracked()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	active, err := t.tapi.ActiveOrdersContext(ctx, "")
+	if err != nil && !errors.Is(err, ErrNoOrders) {
+		return err
+	}
+
+	current := make(map[int]OrderInfoItem, len(ids))
+	for _, id := range ids {
+		key := strconv.Itoa(id)
+		order, isActive := active[key]
+		previous, known, ok := t.state(id)
+		if !ok {
+			continue
+		}
+
+		if isActive && known {
+			info := previous
+			info.Amount = order.Amount
+			info.Status = order.Status
+			current[id] = info
+			continue
+		}
+
+		// orders seen for the first time or no longer active need their start amount and final status
+		orderInfo, err := t.tapi.OrderInfoContext(ctx, key)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			if t.OnError != nil {
+				t.OnError(fmt.Errorf("order %d: %w", id, err))
+			}
+			continue
+		}
+		if info, ok := orderInfo[key]; ok {
+			current[id] = info
+		}
+	}
+
+	now := time.Now()
+	var events []OrderEvent
+	var since time.Time
+	seen := make(map[int]map[string]bool)
+
+	t.mu.Lock()
+	for _, id := range ids {
+		tracked, ok := t.orders[id]
+		info, found := current[id]
+		if !ok || !found {
+			continue
+		}
+		before := tracked.info.Amount
+		if !tracked.known {
+			before = info.StartAmount
+		}
+		tracked.info, tracked.known = info, true
+
+		delta := before.Sub(info.Amount)
+		switch {
+		case info.Status == OrderExecuted || (info.Status == OrderActive && info.Amount.IsZero()):
+			events = append(events, newOrderEvent(id, OrderEventFilled, info, delta, now))
+			delete(t.orders, id)
+		case info.Status == OrderCanceled || info.Status == OrderPartiallyCanceled:
+			if delta.Sign() > 0 {
+				events = append(events, newOrderEvent(id, OrderEventPartiallyFilled, info, delta, now))
+			}
+			events = append(events, newOrderEvent(id, OrderEventCanceled, info, Decimal{}, now))
+			delete(t.orders, id)
+		case delta.Sign() > 0:
+			events = append(events, newOrderEvent(id, OrderEventPartiallyFilled, info, delta, now))
+		}
+		if delta.Sign() > 0 {
+			seen[id] = tracked.trades
+			if created := info.TimestampCreated.Time; since.IsZero() || created.Before(since) {
+				since = created
+			}
+		}
+	}
+	subscribers := append([]chan OrderEvent(nil), t.subscribers...)
+	t.mu.Unlock()
+
+	if len(seen) > 0 {
+		if err := t.attachTrades(ctx, events, seen, since); err != nil && t.OnError != nil {
+			t.OnError(err)
+		}
+	}
+
+	for _, event := range events {
+		for _, ch := range subscribers {
+			select {
+			case ch <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// state returns the last known information of a tracked order and whether it is known.
+func (t *OrderTracker) state(id int) (info OrderInfoItem, known bool, tracked bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[id]
+	if !ok {
+		return OrderInfoItem{}, false, false
+	}
+	return order.info, order.known, true
+}
+
+func newOrderEvent(id int, eventType OrderEventType, info OrderInfoItem, delta Decimal, now time.Time) OrderEvent {
+	return OrderEvent{
+		OrderID:     id,
+		Type:        eventType,
+		Pair:        info.Pair,
+		OrderType:   info.Type,
+		Rate:        info.Rate,
+		StartAmount: info.StartAmount,
+		Filled:      info.StartAmount.Sub(info.Amount),
+		Remaining:   info.Amount,
+		Delta:       delta,
+		Time:        now,
+	}
+}
+
+// attachTrades adds the trades of TradeHistory since the creation of the filled orders to the events with fills, skipping the trades in seen which were attached to earlier events.
+func (t *OrderTracker) attachTrades(ctx context.Context, events []OrderEvent, seen map[int]map[string]bool, since time.Time) error {
+	history, err := t.tapi.TradeHistoryContext(ctx, HistoryFilter{Since: since}, "")
+	if err != nil {
+		if errors.Is(err, ErrNoTrades) {
+			return nil
+		}
+		return err
+	}
+
+	ids := make([]string, 0, len(history))
+	for id := range history {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return history[ids[i]].Timestamp.Before(history[ids[j]].Timestamp.Time) })
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range events {
+		event := &events[i]
+		if event.Delta.Sign() <= 0 {
+			continue
+		}
+		var volume, value Decimal
+		for _, id := range ids {
+			trade := history[id]
+			if trade.OrderID != event.OrderID || seen[event.OrderID][id] {
+				continue
+			}
+			seen[event.OrderID][id] = true
+			event.Trades = append(event.Trades, trade)
+			volume = volume.Add(trade.Amount)
+			value = value.Add(trade.Amount.Mul(trade.Rate))
+		}
+		if !volume.IsZero() {
+			event.AveragePrice = value.Div(volume)
+		}
+	}
+	return nil
+}
+
+func (t *OrderTracker) closeSubscribers() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, ch := range t.subscribers {
+		close(ch)
+	}
+	t.subscribers = nil
+}
@@ -0,0 +1,136 @@
+package wex
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderTracker(t *testing.T) {
+
+	Convey("Order tracker polling a server", t, func() {
+
+		responses := map[string]string{
+			"ActiveOrders": `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","amount":2,"rate":900,"timestamp_created":1500000000,"status":0}}}`,
+			"OrderInfo":    `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","start_amount":2,"amount":2,"rate":900,"timestamp_created":1500000000,"status":0}}}`,
+			"TradeHistory": `{"success":0,"error":"no trades"}`,
+		}
+		requests := map[string]int{}
+		var activePairs []string
+		var mu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			body, _ := ioutil.ReadAll(r.Body)
+			values, _ := url.ParseQuery(string(body))
+			requests[values.Get("method")]++
+			switch {
+			case values.Get("method") == "ActiveOrders":
+				activePairs = append(activePairs, values["pair"]...)
+			case values.Get("method") == "OrderInfo" && values.Get("order_id") == "43":
+				w.Write([]byte(`{"success":0,"error":"invalid order"}`))
+				return
+			}
+			w.Write([]byte(responses[values.Get("method")]))
+		}))
+		defer server.Close()
+		set := func(method string, value string) {
+			mu.Lock()
+			defer mu.Unlock()
+			responses[method] = value
+		}
+		count := func(method string) int {
+			mu.Lock()
+			defer mu.Unlock()
+			return requests[method]
+		}
+
+		tapi := NewTradeAPI("key", "secret", WithTradeURL(server.URL))
+		tracker := NewOrderTracker(tapi, time.Hour)
+		events := tracker.Subscribe(16)
+		tracker.Track(42)
+
+		So(tracker.Poll(context.Background()), ShouldBeNil)
+		So(len(events), ShouldEqual, 0)
+		So(count("OrderInfo"), ShouldEqual, 1)
+		mu.Lock()
+		So(activePairs, ShouldBeEmpty)
+		mu.Unlock()
+
+		Convey("Failing OrderInfo of an order should not stop the other orders", func() {
+			var errs []error
+			tracker.OnError = func(err error) { errs = append(errs, err) }
+			tracker.Track(43)
+			set("ActiveOrders", `{"success":0,"error":"no orders"}`)
+			set("OrderInfo", `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","start_amount":2,"amount":2,"rate":900,"timestamp_created":1500000000,"status":2}}}`)
+			So(tracker.Poll(context.Background()), ShouldBeNil)
+
+			event := <-events
+			So(event.OrderID, ShouldEqual, 42)
+			So(event.Type, ShouldEqual, OrderEventCanceled)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Error(), ShouldStartWith, "order 43:")
+			So(tracker.Tracked(), ShouldResemble, []int{43})
+		})
+
+		Convey("Active orders should be checked without OrderInfo", func() {
+			So(tracker.Poll(context.Background()), ShouldBeNil)
+			So(len(events), ShouldEqual, 0)
+			So(count("OrderInfo"), ShouldEqual, 1)
+		})
+
+		Convey("Partial fills should be reported with their trades", func() {
+			set("ActiveOrders", `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":900,"timestamp_created":1500000000,"status":0}}}`)
+			set("TradeHistory", `{"success":1,"return":{
+				"7":{"pair":"btc_usd","type":"buy","amount":1,"rate":890,"order_id":42,"timestamp":1500000010},
+				"8":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":900,"order_id":42,"timestamp":1500000020},
+				"9":{"pair":"btc_usd","type":"sell","amount":3,"rate":950,"order_id":41,"timestamp":1500000030}}}`)
+			So(tracker.Poll(context.Background()), ShouldBeNil)
+
+			event := <-events
+			So(event.Type, ShouldEqual, OrderEventPartiallyFilled)
+			So(event.Delta.String(), ShouldEqual, "1.5")
+			So(event.Filled.String(), ShouldEqual, "1.5")
+			So(event.Remaining.String(), ShouldEqual, "0.5")
+			So(len(event.Trades), ShouldEqual, 2)
+			So(event.AveragePrice.StringFixed(2), ShouldEqual, "893.33")
+
+			Convey("Filled orders should be reported once with new trades only", func() {
+				set("ActiveOrders", `{"success":0,"error":"no orders"}`)
+				set("OrderInfo", `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","start_amount":2,"amount":0,"rate":900,"timestamp_created":1500000000,"status":1}}}`)
+				set("TradeHistory", `{"success":1,"return":{
+					"7":{"pair":"btc_usd","type":"buy","amount":1,"rate":890,"order_id":42,"timestamp":1500000010},
+					"8":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":900,"order_id":42,"timestamp":1500000020},
+					"10":{"pair":"btc_usd","type":"buy","amount":0.5,"rate":905,"order_id":42,"timestamp":1500000040}}}`)
+				So(tracker.Poll(context.Background()), ShouldBeNil)
+
+				event := <-events
+				So(event.Type, ShouldEqual, OrderEventFilled)
+				So(event.Delta.String(), ShouldEqual, "0.5")
+				So(len(event.Trades), ShouldEqual, 1)
+				So(event.AveragePrice.String(), ShouldEqual, "905")
+				So(tracker.Tracked(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("Canceled orders should be reported", func() {
+			set("ActiveOrders", `{"success":0,"error":"no orders"}`)
+			set("OrderInfo", `{"success":1,"return":{"42":{"pair":"btc_usd","type":"buy","start_amount":2,"amount":2,"rate":900,"timestamp_created":1500000000,"status":2}}}`)
+			So(tracker.Poll(context.Background()), ShouldBeNil)
+
+			event := <-events
+			So(event.Type, ShouldEqual, OrderEventCanceled)
+			So(event.Filled.IsZero(), ShouldBeTrue)
+			So(len(events), ShouldEqual, 0)
+			So(count("TradeHistory"), ShouldEqual, 0)
+			So(tracker.Tracked(), ShouldBeEmpty)
+		})
+	})
+}
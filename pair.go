@@ -0,0 +1,98 @@
+package wex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OrderType is the side of an order.
+type OrderType string
+
+// Order types accepted by Trade API.
+const (
+	Buy  OrderType = "buy"
+	Sell OrderType = "sell"
+)
+
+// Valid reports whether t is Buy or Sell.
+func (t OrderType) Valid() bool {
+	return t == Buy || t == Sell
+}
+
+// Currency is a currency code such as "btc" or "usd".
+type Currency string
+
+// Pair is a currency pair such as "btc_usd", in which the base currency "btc" is traded for the quote currency "usd".
+type Pair string
+
+// NewPair returns the pair of base and quote currencies.
+func NewPair(base Currency, quote Currency) Pair {
+	return Pair(strings.ToLower(string(base)) + "_" + strings.ToLower(string(quote)))
+}
+
+// ParsePair parses a pair such as "btc_usd" or "BTC_USD".
+func ParsePair(s string) (Pair, error) {
+	parts := strings.Split(strings.ToLower(s), "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", PairError{Pair: Pair(s), Reason: "is not in base_quote format"}
+	}
+	return NewPair(Currency(parts[0]), Currency(parts[1])), nil
+}
+
+// Base returns the base currency of the pair, e.g. "btc" for "btc_usd".
+func (p Pair) Base() Currency {
+	if i := strings.Index(string(p), "_"); i >= 0 {
+		return Currency(p[:i])
+	}
+	return Currency(p)
+}
+
+// Quote returns the quote currency of the pair, e.g. "usd" for "btc_usd".
+func (p Pair) Quote() Currency {
+	if i := strings.Index(string(p), "_"); i >= 0 {
+		return Currency(p[i+1:])
+	}
+	return ""
+}
+
+// PairError is returned for pairs which are malformed or not active on the exchange.
+type PairError struct {
+	Pair   Pair
+	Reason string
+}
+
+func (e PairError) Error() string {
+	return fmt.Sprintf("invalid pair %v: %v", e.Pair, e.Reason)
+}
+
+// Is reports whether the target is ErrInvalidPair.
+func (e PairError) Is(target error) bool {
+	return target == ErrInvalidPair
+}
+
+// ValidatePairs checks that the pairs are active on the exchange.
+func (info Info) ValidatePairs(pairs ...Pair) error {
+	for _, pair := range pairs {
+		if _, ok := info.Pairs[pair]; !ok {
+			return PairError{Pair: pair, Reason: "is not an active pair"}
+		}
+	}
+	return nil
+}
+
+// Fees returns the fees of the pairs in percent.
+func (info Info) Fees() map[Pair]Decimal {
+	fees := make(map[Pair]Decimal, len(info.Pairs))
+	for pair, pairInfo := range info.Pairs {
+		fees[pair] = pairInfo.Fee
+	}
+	return fees
+}
+
+func joinPairs(pairs []Pair) string {
+	s := make([]string, len(pairs))
+	for i, pair := range pairs {
+		s[i] = string(pair)
+	}
+	return strings.Join(s, "-")
+}
@@ -0,0 +1,45 @@
+package wex
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPair(t *testing.T) {
+
+	Convey("Pairs should be parsed", t, func() {
+		pair, err := ParsePair("BTC_USD")
+		So(err, ShouldBeNil)
+		So(pair, ShouldEqual, Pair("btc_usd"))
+		So(pair.Base(), ShouldEqual, Currency("btc"))
+		So(pair.Quote(), ShouldEqual, Currency("usd"))
+		So(NewPair("ltc", "btc"), ShouldEqual, Pair("ltc_btc"))
+
+		for _, invalid := range []string{"btcusd", "btc_", "_usd", "btc_usd_eur"} {
+			_, err = ParsePair(invalid)
+			So(errors.Is(err, ErrInvalidPair), ShouldBeTrue)
+		}
+	})
+
+	Convey("Order types should be validated", t, func() {
+		So(Buy.Valid(), ShouldBeTrue)
+		So(Sell.Valid(), ShouldBeTrue)
+		So(OrderType("hold").Valid(), ShouldBeFalse)
+	})
+
+	Convey("Pairs should be validated against the active pairs", t, func() {
+		info := Info{Pairs: map[Pair]InfoPair{"btc_usd": {}, "ltc_usd": {}}}
+		So(info.ValidatePairs("btc_usd", "ltc_usd"), ShouldBeNil)
+
+		err := info.ValidatePairs("btc_usd", "btc_btc")
+		So(errors.Is(err, ErrInvalidPair), ShouldBeTrue)
+		So(err.Error(), ShouldEqual, "invalid pair btc_btc: is not an active pair")
+	})
+
+	Convey("Fees of the pairs should be returned", t, func() {
+		info := Info{Pairs: map[Pair]InfoPair{"btc_usd": {Fee: MustParseDecimal("0.2")}}}
+		So(info.Fees()["btc_usd"].String(), ShouldEqual, "0.2")
+	})
+}
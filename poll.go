@@ -0,0 +1,23 @@
+package wex
+
+import (
+	"context"
+	"time"
+)
+
+// poll calls fn immediately and then every interval until ctx is done, passing errors of fn to onError if it is set.
+func poll(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
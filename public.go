@@ -1,74 +1,74 @@
 package wex
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
 // PublicAPI provides access to such information as tickers of currency pairs, active orders on different pairs, the latest trades for each pair etc.
-type PublicAPI struct{}
+type PublicAPI struct {
+	client *Client
+}
 
 const apiURL = "https://wex.nz/api/3/"
 
 // Info provides all the information about currently active pairs, such as the maximum number of digits after the decimal point, the minimum price, the maximum price, the minimum transaction size, whether the pair is hidden, the commission for each pair.
 func (api *PublicAPI) Info() (Info, error) {
+	return api.InfoContext(context.Background())
+}
 
-	url := apiURL + "info"
-	r, err := http.Get(url)
+// InfoContext provides Info capability with a context for cancellation and deadlines.
+func (api *PublicAPI) InfoContext(ctx context.Context) (Info, error) {
 
+	data := Info{}
+	err := api.query(ctx, "info", nil, nil, &data)
 	if err == nil {
-		data := Info{}
-		err = json.NewDecoder(r.Body).Decode(&data)
-		if err == nil {
-			return data, nil
-		}
+		return data, nil
 	}
 	return Info{}, err
 }
 
 // Ticker provides all the information about currently active pairs, such as: the maximum price, the minimum price, average price, trade volume, trade volume in currency, the last trade, Buy and Sell price.
 // All information is provided over the past 24 hours.
-func (api *PublicAPI) Ticker(currency []string, ignoreInvalid ...bool) (Ticker, error) {
+func (api *PublicAPI) Ticker(pairs []Pair, ignoreInvalid ...bool) (Ticker, error) {
+	return api.TickerContext(context.Background(), pairs, ignoreInvalid...)
+}
 
-	url := apiURL + "ticker/"
-	for _, c := range currency {
-		url = url + c + "-"
-	}
+// TickerContext provides Ticker capability with a context for cancellation and deadlines.
+func (api *PublicAPI) TickerContext(ctx context.Context, pairs []Pair, ignoreInvalid ...bool) (Ticker, error) {
+
+	params := url.Values{}
 	if len(ignoreInvalid) > 0 && ignoreInvalid[0] {
-		url += "?ignore_invalid=1"
+		params.Set("ignore_invalid", "1")
 	}
-	r, err := http.Get(url)
 
+	data := make(Ticker, len(pairs))
+	err := api.query(ctx, "ticker", pairs, params, &data)
 	if err == nil {
-		data := make(Ticker, len(currency))
-		err = json.NewDecoder(r.Body).Decode(&data)
-		if err == nil {
-			return data, nil
-		}
+		return data, nil
 	}
 
 	return nil, err
 }
 
 // Depth provides the information about active orders on the pair.
-func (api *PublicAPI) Depth(currency []string, limit int) (Depth, error) {
+func (api *PublicAPI) Depth(pairs []Pair, limit int) (Depth, error) {
+	return api.DepthContext(context.Background(), pairs, limit)
+}
 
-	url := apiURL + "depth/"
-	for _, c := range currency {
-		url = url + c + "-"
-	}
-	if limit > 0 {
-		url = url + "?limit=" + strconv.Itoa(limit)
-	}
-	r, err := http.Get(url)
+// DepthContext provides Depth capability with a context for cancellation and deadlines.
+func (api *PublicAPI) DepthContext(ctx context.Context, pairs []Pair, limit int) (Depth, error) {
 
+	data := make(Depth, len(pairs))
+	err := api.query(ctx, "depth", pairs, limitParams(limit), &data)
 	if err == nil {
-		data := make(Depth, len(currency))
-		err = json.NewDecoder(r.Body).Decode(&data)
-		if err == nil {
-			return data, nil
-		}
+		return data, nil
 	}
 
 	return nil, err
@@ -76,24 +76,98 @@ func (api *PublicAPI) Depth(currency []string, limit int) (Depth, error) {
 }
 
 // Trades provides the information about the last trades.
-func (api *PublicAPI) Trades(currency []string, limit int) (Trades, error) {
+func (api *PublicAPI) Trades(pairs []Pair, limit int) (Trades, error) {
+	return api.TradesContext(context.Background(), pairs, limit)
+}
+
+// TradesContext provides Trades capability with a context for cancellation and deadlines.
+func (api *PublicAPI) TradesContext(ctx context.Context, pairs []Pair, limit int) (Trades, error) {
 
-	url := apiURL + "trades/"
-	for _, c := range currency {
-		url = url + c + "-"
+	data := make(Trades, len(pairs))
+	err := api.query(ctx, "trades", pairs, limitParams(limit), &data)
+	if err == nil {
+		return data, nil
 	}
+
+	return nil, err
+}
+
+func limitParams(limit int) url.Values {
+	params := url.Values{}
 	if limit > 0 {
-		url = url + "?limit=" + strconv.Itoa(limit)
+		params.Set("limit", strconv.Itoa(limit))
 	}
-	r, err := http.Get(url)
+	return params
+}
 
-	if err == nil {
-		data := make(Trades, len(currency))
-		err = json.NewDecoder(r.Body).Decode(&data)
-		if err == nil {
-			return data, nil
+// query requests method of Public API for the pairs and decodes the response into v, retrying according to the retry policy.
+func (api *PublicAPI) query(ctx context.Context, method string, pairs []Pair, params url.Values, v interface{}) error {
+	client := clientOrDefault(api.client)
+
+	url := client.publicURL() + method
+	if len(pairs) > 0 {
+		url += "/" + joinPairs(pairs)
+	}
+	if len(params) > 0 {
+		url += "?" + params.Encode()
+	}
+
+	return client.RetryPolicy.do(ctx, func() error {
+		r, err := api.get(ctx, url)
+		if err != nil {
+			return err
 		}
+		return decodePublicResponse(r, v)
+	})
+}
+
+func (api *PublicAPI) get(ctx context.Context, url string) (*http.Response, error) {
+	client := clientOrDefault(api.client)
+	if err := client.PublicLimiter.Wait(ctx); err != nil {
+		return nil, err
 	}
 
-	return nil, err
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	return client.do(req)
+}
+
+// decodePublicResponse decodes the response body into v and closes it.
+// Responses with unexpected status or content type, such as HTML error pages, are reported as StatusError or DecodeError and error responses of Public API as PublicError.
+func decodePublicResponse(r *http.Response, v interface{}) error {
+	defer r.Body.Close()
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return StatusError{StatusCode: r.StatusCode, Status: r.Status, Body: body}
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && mediaType != "text/plain") {
+			return DecodeError{Body: body, Err: fmt.Errorf("unexpected content type %q", contentType)}
+		}
+	}
+
+	envelope := struct {
+		Success *int   `json:"success"`
+		Error   string `json:"error"`
+	}{}
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return DecodeError{Body: body, Err: err}
+	}
+	if envelope.Success != nil && *envelope.Success == 0 {
+		return PublicError{Message: envelope.Error}
+	}
+
+	if err = json.Unmarshal(body, v); err != nil {
+		return DecodeError{Body: body, Err: err}
+	}
+	return nil
 }
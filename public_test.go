@@ -1,8 +1,14 @@
 package wex
 
 import (
-	. "github.com/smartystreets/goconvey/convey"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
 )
 
 var api = PublicAPI{}
@@ -10,7 +16,7 @@ var api = PublicAPI{}
 func TestTicker(t *testing.T) {
 
 	Convey("Ticker data for BTC-USD", t, func() {
-		tickers, err := api.Ticker([]string{"btc_usd"})
+		tickers, err := api.Ticker([]Pair{"btc_usd"})
 
 		Convey("No error should occur", func() {
 			So(err, ShouldBeNil)
@@ -24,7 +30,7 @@ func TestTicker(t *testing.T) {
 	})
 
 	Convey("Ticker data for BTC-USD and BTC-BTC", t, func() {
-		_, err := api.Ticker([]string{"btc_usd", "btc_btc"})
+		_, err := api.Ticker([]Pair{"btc_usd", "btc_btc"})
 
 		Convey("Error should occur", func() {
 			So(err, ShouldNotBeNil)
@@ -32,7 +38,7 @@ func TestTicker(t *testing.T) {
 	})
 
 	Convey("Ticker data for BTC-USD and BTC-BTC with the ignore invalid flag", t, func() {
-		tickers, err := api.Ticker([]string{"btc_usd", "btc_btc"}, true)
+		tickers, err := api.Ticker([]Pair{"btc_usd", "btc_btc"}, true)
 
 		Convey("No error should occur", func() {
 			So(err, ShouldBeNil)
@@ -65,7 +71,7 @@ func TestInfo(t *testing.T) {
 func TestDepth(t *testing.T) {
 
 	Convey("Depth data", t, func() {
-		depth, err := api.Depth([]string{"btc_usd"}, 1)
+		depth, err := api.Depth([]Pair{"btc_usd"}, 1)
 
 		Convey("No error should occur", func() {
 			So(err, ShouldBeNil)
@@ -81,7 +87,7 @@ func TestDepth(t *testing.T) {
 func TestTrade(t *testing.T) {
 
 	Convey("Trade data", t, func() {
-		trade, err := api.Trades([]string{"btc_usd"}, 1)
+		trade, err := api.Trades([]Pair{"btc_usd"}, 1)
 
 		Convey("No error should occur", func() {
 			So(err, ShouldBeNil)
@@ -93,3 +99,109 @@ func TestTrade(t *testing.T) {
 		})
 	})
 }
+
+// closeTracker records whether response bodies are closed.
+type closeTracker struct {
+	transport http.RoundTripper
+	open      int
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	tracker *closeTracker
+}
+
+func (b trackedBody) Close() error {
+	b.tracker.open--
+	return b.ReadCloser.Close()
+}
+
+func (t *closeTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err == nil {
+		t.open++
+		resp.Body = trackedBody{resp.Body, t}
+	}
+	return resp, err
+}
+
+func TestPublicPipeline(t *testing.T) {
+
+	Convey("Public API against a local server", t, func() {
+
+		var requested *url.URL
+		contentType := "application/json"
+		status := http.StatusOK
+		body := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = r.URL
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		tracker := &closeTracker{transport: http.DefaultTransport}
+		api := NewPublicAPI(WithPublicURL(server.URL), WithHTTPClient(&http.Client{Transport: tracker}))
+
+		Convey("Info should be decoded", func() {
+			body = `{"server_time":1500000000,"pairs":{"btc_usd":{"decimal_places":3,"min_price":0.1,"max_price":400,"min_amount":0.001,"hidden":0,"fee":0.2}}}`
+			info, err := api.Info()
+			So(err, ShouldBeNil)
+			So(requested.Path, ShouldEqual, "/info")
+			So(info.Pairs["btc_usd"].DecimalPlaces, ShouldEqual, 3)
+			So(info.Pairs["btc_usd"].Fee.String(), ShouldEqual, "0.2")
+		})
+
+		Convey("Ticker should request all pairs", func() {
+			body = `{"btc_usd":{"last":10},"ltc_usd":{"last":2}}`
+			ticker, err := api.Ticker([]Pair{"btc_usd", "ltc_usd"}, true)
+			So(err, ShouldBeNil)
+			So(requested.Path, ShouldEqual, "/ticker/btc_usd-ltc_usd")
+			So(requested.Query().Get("ignore_invalid"), ShouldEqual, "1")
+			So(ticker["ltc_usd"].Last.String(), ShouldEqual, "2")
+		})
+
+		Convey("Depth should pass the limit", func() {
+			body = `{"btc_usd":{"asks":[[10.5,1]],"bids":[[10,2]]}}`
+			depth, err := api.Depth([]Pair{"btc_usd"}, 1)
+			So(err, ShouldBeNil)
+			So(requested.Path, ShouldEqual, "/depth/btc_usd")
+			So(requested.Query().Get("limit"), ShouldEqual, "1")
+			So(depth["btc_usd"].Bids[0].Price.String(), ShouldEqual, "10")
+			So(depth["btc_usd"].Bids[0].Amount.String(), ShouldEqual, "2")
+		})
+
+		Convey("Trades should be decoded", func() {
+			body = `{"btc_usd":[{"type":"ask","price":10,"amount":1,"tid":5,"timestamp":1500000000}]}`
+			trades, err := api.Trades([]Pair{"btc_usd"}, 0)
+			So(err, ShouldBeNil)
+			So(requested.RawQuery, ShouldEqual, "")
+			So(trades["btc_usd"][0].TID, ShouldEqual, 5)
+		})
+
+		Convey("HTML page should be reported as malformed response", func() {
+			contentType = "text/html"
+			body = `<html>Checking your browser</html>`
+			_, err := api.Info()
+			So(errors.Is(err, ErrMalformedResponse), ShouldBeTrue)
+		})
+
+		Convey("Unexpected status should be reported", func() {
+			status = http.StatusServiceUnavailable
+			body = `{}`
+			_, err := api.Info()
+			So(errors.Is(err, ErrHTTPStatus), ShouldBeTrue)
+		})
+
+		Convey("Error envelope should be reported", func() {
+			body = `{"success":0,"error":"Invalid pair name: btc_btc"}`
+			_, err := api.Ticker([]Pair{"btc_btc"})
+			So(errors.Is(err, ErrInvalidPair), ShouldBeTrue)
+		})
+
+		Reset(func() {
+			So(tracker.open, ShouldEqual, 0)
+		})
+	})
+}
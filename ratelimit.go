@@ -0,0 +1,123 @@
+package wex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate of requests sent to the exchange.
+// The bucket holds up to burst tokens and is refilled at the configured rate; each request takes one token.
+type RateLimiter struct {
+	// NoWait makes requests fail with RateLimitError instead of waiting when no token is available.
+	NoWait bool
+	// OnWait is called with the time a request waited for a token, which is zero if a token was available immediately.
+	OnWait func(wait time.Duration)
+	// OnReject is called when a request fails because no token is available.
+	OnReject func()
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing requests per period on average, with bursts of up to burst requests.
+// It panics if requests or per is not positive.
+func NewRateLimiter(requests int, per time.Duration, burst int) *RateLimiter {
+	if requests <= 0 || per <= 0 {
+		panic(fmt.Sprintf("wex: NewRateLimiter needs positive requests and period, got %d per %v", requests, per))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   float64(requests) / per.Seconds(),
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// WithPublicRateLimit sets the rate limiter of Public API requests.
+func WithPublicRateLimit(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.PublicLimiter = limiter
+	}
+}
+
+// WithTradeRateLimit sets the rate limiter of Trade API requests.
+func WithTradeRateLimit(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.TradeLimiter = limiter
+	}
+}
+
+// Wait takes a token, waiting until one is available or ctx is done.
+// If NoWait is set, Wait fails with RateLimitError instead of waiting.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	wait, ok := l.reserve(time.Now())
+	if !ok {
+		if l.OnReject != nil {
+			l.OnReject()
+		}
+		return RateLimitError{RetryAfter: wait}
+	}
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			l.cancel()
+			return ctx.Err()
+		}
+	}
+
+	if l.OnWait != nil {
+		l.OnWait(wait)
+	}
+	return nil
+}
+
+// reserve takes a token and returns the time until it is available.
+// With NoWait, the token is taken only if it is available immediately.
+func (l *RateLimiter) reserve(now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		if l.NoWait {
+			return wait, false
+		}
+	}
+	l.tokens--
+	return wait, true
+}
+
+// cancel returns a token taken by a request which did not wait for it.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
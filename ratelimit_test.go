@@ -0,0 +1,96 @@
+package wex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRateLimiter(t *testing.T) {
+
+	Convey("Rate limiter with a burst of 2 requests", t, func() {
+
+		limiter := NewRateLimiter(10, time.Second, 2)
+		var waits []time.Duration
+		limiter.OnWait = func(wait time.Duration) { waits = append(waits, wait) }
+
+		Convey("Burst should be allowed without waiting", func() {
+			So(limiter.Wait(context.Background()), ShouldBeNil)
+			So(limiter.Wait(context.Background()), ShouldBeNil)
+			So(waits, ShouldResemble, []time.Duration{0, 0})
+		})
+
+		Convey("Requests exceeding the burst should wait", func() {
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				So(limiter.Wait(context.Background()), ShouldBeNil)
+			}
+			So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 90*time.Millisecond)
+			So(waits[2], ShouldBeGreaterThan, 0)
+		})
+
+		Convey("Waiting should stop when the context is done", func() {
+			limiter = NewRateLimiter(1, time.Hour, 1)
+			So(limiter.Wait(context.Background()), ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			So(limiter.Wait(ctx), ShouldEqual, context.DeadlineExceeded)
+		})
+
+		Convey("With NoWait, exhausted budget should be reported as error", func() {
+			rejected := 0
+			limiter.NoWait = true
+			limiter.OnReject = func() { rejected++ }
+
+			So(limiter.Wait(context.Background()), ShouldBeNil)
+			So(limiter.Wait(context.Background()), ShouldBeNil)
+			err := limiter.Wait(context.Background())
+			So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+			So(err.(RateLimitError).RetryAfter, ShouldBeGreaterThan, 0)
+			So(rejected, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Rates which are not positive should be rejected", t, func() {
+		So(func() { NewRateLimiter(0, time.Second, 1) }, ShouldPanic)
+		So(func() { NewRateLimiter(-1, time.Second, 1) }, ShouldPanic)
+		So(func() { NewRateLimiter(1, 0, 1) }, ShouldPanic)
+		So(func() { NewRateLimiter(1, time.Second, 0) }, ShouldNotPanic)
+	})
+
+	Convey("Public and Trade APIs with separate budgets", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				w.Write([]byte(`{"success":1,"return":{}}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		publicLimiter := NewRateLimiter(1, time.Hour, 1)
+		publicLimiter.NoWait = true
+		tradeLimiter := NewRateLimiter(1, time.Hour, 1)
+		tradeLimiter.NoWait = true
+
+		api := New(WithPublicURL(server.URL), WithTradeURL(server.URL),
+			WithPublicRateLimit(publicLimiter), WithTradeRateLimit(tradeLimiter))
+
+		_, err := api.Public.Info()
+		So(err, ShouldBeNil)
+		_, err = api.Public.Info()
+		So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+
+		_, err = api.Trade.GetInfo()
+		So(err, ShouldBeNil)
+		_, err = api.Trade.GetInfo()
+		So(errors.Is(err, ErrRateLimited), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,206 @@
+// Package report computes realized and unrealized profit and loss of trades from the trade history of a WEX account, matching sales against tax lots of purchases.
+//
+// Example usage:
+//
+//	history, err := api.Trade.TradeHistory(wex.HistoryFilter{}, "")
+//	ticker, err := api.Public.Ticker([]wex.Pair{"btc_usd"})
+//
+//	r := report.New(history.Items(), report.Options{
+//		Method: report.FIFO,
+//		Period: report.Monthly,
+//		Fees:   info.Fees(),
+//		Prices: report.PricesFromTicker(ticker),
+//	})
+//	r.WritePeriodsCSV(os.Stdout)
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+)
+
+// Method is the method of matching sales against lots.
+type Method string
+
+// Lot matching methods.
+const (
+	// FIFO matches sales against the oldest lots first.
+	FIFO Method = "fifo"
+	// LIFO matches sales against the newest lots first.
+	LIFO Method = "lifo"
+	// AverageCost pools all purchases of a pair into a single lot at their average cost.
+	AverageCost Method = "average"
+)
+
+// ParseMethod parses "fifo", "lifo" or "average".
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(s)); m {
+	case FIFO, LIFO, AverageCost:
+		return m, nil
+	}
+	return "", fmt.Errorf("report: unknown method %q", s)
+}
+
+// Lot is an amount of the base currency of a pair bought at a cost in the quote currency, fees included.
+type Lot struct {
+	Pair      wex.Pair    `json:"pair"`
+	TradeID   int         `json:"trade_id"`
+	Time      time.Time   `json:"time"`
+	Amount    wex.Decimal `json:"amount"`
+	Cost      wex.Decimal `json:"cost"`
+	Remaining wex.Decimal `json:"remaining"`
+}
+
+// UnitCost returns the cost of a unit of the lot.
+func (l Lot) UnitCost() wex.Decimal {
+	if l.Amount.IsZero() {
+		return wex.Decimal{}
+	}
+	return l.Cost.Div(l.Amount)
+}
+
+// Realization is a sale matched against lots.
+// Unmatched is the amount sold which exceeded the open lots, e.g. bought before the history starts. It is realized at zero cost.
+type Realization struct {
+	Pair      wex.Pair    `json:"pair"`
+	TradeID   int         `json:"trade_id"`
+	Time      time.Time   `json:"time"`
+	Amount    wex.Decimal `json:"amount"`
+	Proceeds  wex.Decimal `json:"proceeds"`
+	Cost      wex.Decimal `json:"cost"`
+	Fee       wex.Decimal `json:"fee"`
+	PnL       wex.Decimal `json:"pnl"`
+	Unmatched wex.Decimal `json:"unmatched"`
+}
+
+// Position is the state of a pair after matching its trades.
+type Position struct {
+	Pair     wex.Pair
+	Lots     []Lot
+	Realized []Realization
+	// Fees are the fees paid for purchases, in the quote currency. Fees of sales are in their Realization.
+	Fees wex.Decimal
+}
+
+// Amount returns the amount of the open lots.
+func (p *Position) Amount() wex.Decimal {
+	var amount wex.Decimal
+	for _, lot := range p.Lots {
+		amount = amount.Add(lot.Remaining)
+	}
+	return amount
+}
+
+// Cost returns the remaining cost of the open lots.
+func (p *Position) Cost() wex.Decimal {
+	var cost wex.Decimal
+	for _, lot := range p.Lots {
+		cost = cost.Add(lot.Remaining.Mul(lot.UnitCost()))
+	}
+	return cost
+}
+
+// RealizedPnL returns the total profit and loss of the sales.
+func (p *Position) RealizedPnL() wex.Decimal {
+	var pnl wex.Decimal
+	for _, realization := range p.Realized {
+		pnl = pnl.Add(realization.PnL)
+	}
+	return pnl
+}
+
+// UnrealizedPnL returns the profit and loss of selling the open lots at price, with the fee in percent deducted from the proceeds.
+func (p *Position) UnrealizedPnL(price wex.Decimal, fee wex.Decimal) wex.Decimal {
+	value := p.Amount().Mul(price).Mul(keep(fee))
+	return value.Sub(p.Cost())
+}
+
+// keep returns the part of an amount kept after a fee in percent.
+func keep(fee wex.Decimal) wex.Decimal {
+	return wex.NewDecimalFromInt(1).Sub(fee.Div(wex.NewDecimalFromInt(100)))
+}
+
+// Match builds lots of the purchases and matches the sales against them with the method, given fees in percent per pair.
+// Trades are processed in chronological order, regardless of their order in trades.
+func Match(trades []wex.TradeHistoryItem, method Method, fees map[wex.Pair]wex.Decimal) map[wex.Pair]*Position {
+	sorted := append([]wex.TradeHistoryItem(nil), trades...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp.Time) {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp.Time)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	positions := make(map[wex.Pair]*Position)
+	for _, trade := range sorted {
+		pair := wex.Pair(strings.ToLower(string(trade.Pair)))
+		position, ok := positions[pair]
+		if !ok {
+			position = &Position{Pair: pair}
+			positions[pair] = position
+		}
+
+		fee := fees[pair]
+		total := trade.Amount.Mul(trade.Rate)
+		if trade.Type == wex.Sell {
+			position.sell(trade, total, fee, method)
+			continue
+		}
+
+		// the fee of a purchase is deducted from the amount received, so the cost is spread over less units
+		received := trade.Amount.Mul(keep(fee))
+		position.Fees = position.Fees.Add(trade.Amount.Sub(received).Mul(trade.Rate))
+		lot := Lot{Pair: pair, TradeID: trade.ID, Time: trade.Timestamp.Time, Amount: received, Cost: total, Remaining: received}
+		if method == AverageCost && len(position.Lots) > 0 {
+			pooled := position.Lots[0]
+			cost := position.Cost().Add(total)
+			pooled.Remaining = pooled.Remaining.Add(received)
+			pooled.Amount, pooled.Cost = pooled.Remaining, cost
+			position.Lots[0] = pooled
+			continue
+		}
+		position.Lots = append(position.Lots, lot)
+	}
+	return positions
+}
+
+func (p *Position) sell(trade wex.TradeHistoryItem, total wex.Decimal, fee wex.Decimal, method Method) {
+	proceeds := total.Mul(keep(fee))
+	realization := Realization{
+		Pair:     p.Pair,
+		TradeID:  trade.ID,
+		Time:     trade.Timestamp.Time,
+		Amount:   trade.Amount,
+		Proceeds: proceeds,
+		Fee:      total.Sub(proceeds),
+	}
+
+	remaining := trade.Amount
+	for remaining.Sign() > 0 && len(p.Lots) > 0 {
+		i := 0
+		if method == LIFO {
+			i = len(p.Lots) - 1
+		}
+		lot := &p.Lots[i]
+
+		matched := remaining
+		if lot.Remaining.Cmp(matched) < 0 {
+			matched = lot.Remaining
+		}
+		realization.Cost = realization.Cost.Add(matched.Mul(lot.UnitCost()))
+		lot.Remaining = lot.Remaining.Sub(matched)
+		remaining = remaining.Sub(matched)
+
+		if lot.Remaining.IsZero() {
+			p.Lots = append(p.Lots[:i], p.Lots[i+1:]...)
+		}
+	}
+
+	realization.Unmatched = remaining
+	realization.PnL = realization.Proceeds.Sub(realization.Cost)
+	p.Realized = append(p.Realized, realization)
+}
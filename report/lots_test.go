@@ -0,0 +1,77 @@
+package report
+
+import (
+	"testing"
+
+	wex "github.com/onuryilmaz/go-wex"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func trade(id int, orderType wex.OrderType, amount string, rate string, timestamp int64) wex.TradeHistoryItem {
+	return wex.TradeHistoryItem{
+		ID:        id,
+		Pair:      "btc_usd",
+		Type:      orderType,
+		Amount:    wex.MustParseDecimal(amount),
+		Rate:      wex.MustParseDecimal(rate),
+		Timestamp: wex.UnixTime(timestamp),
+	}
+}
+
+func TestMatch(t *testing.T) {
+
+	Convey("Trades bought at 100 and 200 and partially sold at 300", t, func() {
+
+		trades := []wex.TradeHistoryItem{
+			trade(3, wex.Sell, "1.5", "300", 1500000300),
+			trade(1, wex.Buy, "1", "100", 1500000100),
+			trade(2, wex.Buy, "1", "200", 1500000200),
+		}
+
+		Convey("FIFO should match the oldest lots first", func() {
+			position := Match(trades, FIFO, nil)["btc_usd"]
+			So(len(position.Realized), ShouldEqual, 1)
+			So(position.Realized[0].Cost.String(), ShouldEqual, "200")
+			So(position.RealizedPnL().String(), ShouldEqual, "250")
+			So(position.Amount().String(), ShouldEqual, "0.5")
+			So(position.Cost().String(), ShouldEqual, "100")
+			So(position.UnrealizedPnL(wex.NewDecimalFromInt(300), wex.Decimal{}).String(), ShouldEqual, "50")
+		})
+
+		Convey("LIFO should match the newest lots first", func() {
+			position := Match(trades, LIFO, nil)["btc_usd"]
+			So(position.Realized[0].Cost.String(), ShouldEqual, "250")
+			So(position.Cost().String(), ShouldEqual, "50")
+		})
+
+		Convey("Average cost should pool the lots", func() {
+			position := Match(trades, AverageCost, nil)["btc_usd"]
+			So(position.Realized[0].Cost.String(), ShouldEqual, "225")
+			So(position.Cost().String(), ShouldEqual, "75")
+			So(len(position.Lots), ShouldEqual, 1)
+		})
+
+		Convey("Fees should be deducted from the amounts received", func() {
+			position := Match(trades, FIFO, map[wex.Pair]wex.Decimal{"btc_usd": wex.NewDecimalFromInt(1)})["btc_usd"]
+			So(position.Fees.String(), ShouldEqual, "3")
+			So(position.Realized[0].Proceeds.String(), ShouldEqual, "445.5")
+			So(position.Realized[0].Fee.String(), ShouldEqual, "4.5")
+			So(position.Amount().String(), ShouldEqual, "0.48")
+		})
+
+		Convey("Sales exceeding the lots should be unmatched", func() {
+			position := Match(append(trades, trade(4, wex.Sell, "1", "300", 1500000400)), FIFO, nil)["btc_usd"]
+			So(position.Realized[1].Unmatched.String(), ShouldEqual, "0.5")
+			So(position.Realized[1].Cost.String(), ShouldEqual, "100")
+			So(position.Lots, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Methods should be parsed", t, func() {
+		method, err := ParseMethod("LIFO")
+		So(err, ShouldBeNil)
+		So(method, ShouldEqual, LIFO)
+		_, err = ParseMethod("hifo")
+		So(err, ShouldNotBeNil)
+	})
+}
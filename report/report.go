@@ -0,0 +1,197 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	wex "github.com/onuryilmaz/go-wex"
+)
+
+// Period is the length of the periods realized profit and loss is reported for.
+type Period string
+
+// Report periods, in UTC.
+const (
+	Daily   Period = "day"
+	Monthly Period = "month"
+	Yearly  Period = "year"
+	// Total reports a single period for the whole history.
+	Total Period = "total"
+)
+
+// ParsePeriod parses "day", "month", "year" or "total".
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(s)); p {
+	case Daily, Monthly, Yearly, Total:
+		return p, nil
+	}
+	return "", fmt.Errorf("report: unknown period %q", s)
+}
+
+// label returns the name of the period containing t, e.g. "2017-07" for Monthly.
+func (p Period) label(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case Daily:
+		return t.Format("2006-01-02")
+	case Monthly:
+		return t.Format("2006-01")
+	case Yearly:
+		return t.Format("2006")
+	}
+	return "total"
+}
+
+// Options configure a report.
+type Options struct {
+	// Method is the lot matching method, FIFO by default.
+	Method Method
+	// Period is the length of the periods, Total by default.
+	Period Period
+	// Fees are the fees of pairs in percent, e.g. from Info.Fees.
+	Fees map[wex.Pair]wex.Decimal
+	// Prices are the current prices of pairs used for unrealized profit and loss, e.g. from PricesFromTicker.
+	Prices map[wex.Pair]wex.Decimal
+}
+
+// PricesFromTicker returns the last prices of the pairs of ticker.
+func PricesFromTicker(ticker wex.Ticker) map[wex.Pair]wex.Decimal {
+	prices := make(map[wex.Pair]wex.Decimal, len(ticker))
+	for pair, tickerPair := range ticker {
+		prices[pair] = tickerPair.Last
+	}
+	return prices
+}
+
+// PeriodRow is the realized profit and loss of a pair within a period. Amounts are in the base currency, values in the quote currency.
+// Unmatched is the amount sold without open lots, e.g. bought before the history starts, which is realized at zero cost and so overstates PnL.
+type PeriodRow struct {
+	Period    string      `json:"period"`
+	Pair      wex.Pair    `json:"pair"`
+	Sales     int         `json:"sales"`
+	Amount    wex.Decimal `json:"amount"`
+	Proceeds  wex.Decimal `json:"proceeds"`
+	Cost      wex.Decimal `json:"cost"`
+	Fees      wex.Decimal `json:"fees"`
+	PnL       wex.Decimal `json:"pnl"`
+	Unmatched wex.Decimal `json:"unmatched"`
+}
+
+// PositionRow is the open position and the total profit and loss of a pair.
+// Unrealized profit and loss is only set when the price of the pair is known.
+type PositionRow struct {
+	Pair          wex.Pair     `json:"pair"`
+	Amount        wex.Decimal  `json:"amount"`
+	Cost          wex.Decimal  `json:"cost"`
+	Price         *wex.Decimal `json:"price"`
+	RealizedPnL   wex.Decimal  `json:"realized_pnl"`
+	UnrealizedPnL *wex.Decimal `json:"unrealized_pnl"`
+}
+
+// Report is the profit and loss of trades per period and pair.
+type Report struct {
+	Method    Method        `json:"method"`
+	Period    Period        `json:"period"`
+	Periods   []PeriodRow   `json:"periods"`
+	Positions []PositionRow `json:"positions"`
+}
+
+// New matches the trades with the options and reports their profit and loss.
+// Rows are sorted by period and pair.
+func New(trades []wex.TradeHistoryItem, options Options) Report {
+	if options.Method == "" {
+		options.Method = FIFO
+	}
+	if options.Period == "" {
+		options.Period = Total
+	}
+
+	positions := Match(trades, options.Method, options.Fees)
+	report := Report{Method: options.Method, Period: options.Period}
+
+	type rowKey struct {
+		period string
+		pair   wex.Pair
+	}
+	rows := make(map[rowKey]*PeriodRow)
+	for pair, position := range positions {
+		for _, realization := range position.Realized {
+			key := rowKey{options.Period.label(realization.Time), pair}
+			row, ok := rows[key]
+			if !ok {
+				row = &PeriodRow{Period: key.period, Pair: pair}
+				rows[key] = row
+			}
+			row.Sales++
+			row.Amount = row.Amount.Add(realization.Amount)
+			row.Proceeds = row.Proceeds.Add(realization.Proceeds)
+			row.Cost = row.Cost.Add(realization.Cost)
+			row.Fees = row.Fees.Add(realization.Fee)
+			row.PnL = row.PnL.Add(realization.PnL)
+			row.Unmatched = row.Unmatched.Add(realization.Unmatched)
+		}
+
+		row := PositionRow{Pair: pair, Amount: position.Amount(), Cost: position.Cost(), RealizedPnL: position.RealizedPnL()}
+		if price, ok := options.Prices[pair]; ok {
+			unrealized := position.UnrealizedPnL(price, options.Fees[pair])
+			row.Price, row.UnrealizedPnL = &price, &unrealized
+		}
+		report.Positions = append(report.Positions, row)
+	}
+
+	for _, row := range rows {
+		report.Periods = append(report.Periods, *row)
+	}
+	sort.Slice(report.Periods, func(i, j int) bool {
+		a, b := report.Periods[i], report.Periods[j]
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		return a.Pair < b.Pair
+	})
+	sort.Slice(report.Positions, func(i, j int) bool { return report.Positions[i].Pair < report.Positions[j].Pair })
+	return report
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WritePeriodsCSV writes the period rows as CSV with a header line.
+func (r Report) WritePeriodsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"period", "pair", "sales", "amount", "proceeds", "cost", "fees", "pnl", "unmatched"})
+	for _, row := range r.Periods {
+		writer.Write([]string{row.Period, string(row.Pair), strconv.Itoa(row.Sales),
+			row.Amount.String(), row.Proceeds.String(), row.Cost.String(), row.Fees.String(), row.PnL.String(), row.Unmatched.String()})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WritePositionsCSV writes the position rows as CSV with a header line. Unknown prices and unrealized profit and loss are empty.
+func (r Report) WritePositionsCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"pair", "amount", "cost", "price", "realized_pnl", "unrealized_pnl"})
+	for _, row := range r.Positions {
+		price, unrealized := "", ""
+		if row.Price != nil {
+			price = row.Price.String()
+		}
+		if row.UnrealizedPnL != nil {
+			unrealized = row.UnrealizedPnL.String()
+		}
+		writer.Write([]string{string(row.Pair), row.Amount.String(), row.Cost.String(), price, row.RealizedPnL.String(), unrealized})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	wex "github.com/onuryilmaz/go-wex"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReport(t *testing.T) {
+
+	Convey("Monthly report of trades in two months", t, func() {
+
+		trades := []wex.TradeHistoryItem{
+			trade(1, wex.Buy, "2", "100", 1498867200),   // 2017-07-01
+			trade(2, wex.Sell, "1", "150", 1499990400),  // 2017-07-14
+			trade(3, wex.Sell, "0.5", "80", 1501632000), // 2017-08-02
+		}
+		r := New(trades, Options{
+			Period: Monthly,
+			Prices: map[wex.Pair]wex.Decimal{"btc_usd": wex.NewDecimalFromInt(120)},
+		})
+
+		Convey("Realized profit and loss should be reported per month", func() {
+			So(r.Method, ShouldEqual, FIFO)
+			So(len(r.Periods), ShouldEqual, 2)
+			So(r.Periods[0].Period, ShouldEqual, "2017-07")
+			So(r.Periods[0].PnL.String(), ShouldEqual, "50")
+			So(r.Periods[1].Period, ShouldEqual, "2017-08")
+			So(r.Periods[1].PnL.String(), ShouldEqual, "-10")
+		})
+
+		Convey("Open positions should be valued at current prices", func() {
+			So(len(r.Positions), ShouldEqual, 1)
+			So(r.Positions[0].Amount.String(), ShouldEqual, "0.5")
+			So(r.Positions[0].RealizedPnL.String(), ShouldEqual, "40")
+			So(r.Positions[0].UnrealizedPnL.String(), ShouldEqual, "10")
+		})
+
+		Convey("Report should be exported as CSV", func() {
+			var buf bytes.Buffer
+			So(r.WritePeriodsCSV(&buf), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "period,pair,sales,amount,proceeds,cost,fees,pnl,unmatched\n"+
+				"2017-07,btc_usd,1,1,150,100,0,50,0\n"+
+				"2017-08,btc_usd,1,0.5,40,50,0,-10,0\n")
+
+			buf.Reset()
+			So(New(trades, Options{}).WritePositionsCSV(&buf), ShouldBeNil)
+			So(buf.String(), ShouldEqual, "pair,amount,cost,price,realized_pnl,unrealized_pnl\nbtc_usd,0.5,50,,40,\n")
+		})
+
+		Convey("Report should be exported as JSON", func() {
+			var buf bytes.Buffer
+			So(r.WriteJSON(&buf), ShouldBeNil)
+
+			var decoded Report
+			So(json.Unmarshal(buf.Bytes(), &decoded), ShouldBeNil)
+			So(decoded.Period, ShouldEqual, Monthly)
+			So(decoded.Periods[1].Proceeds.String(), ShouldEqual, "40")
+			So(decoded.Positions[0].Price.String(), ShouldEqual, "120")
+		})
+	})
+
+	Convey("Sales exceeding the purchases should be reported as unmatched", t, func() {
+		r := New([]wex.TradeHistoryItem{
+			trade(1, wex.Buy, "1", "100", 1498867200),
+			trade(2, wex.Sell, "1.5", "150", 1499990400),
+		}, Options{})
+
+		So(len(r.Periods), ShouldEqual, 1)
+		So(r.Periods[0].Unmatched.String(), ShouldEqual, "0.5")
+		So(r.Periods[0].Cost.String(), ShouldEqual, "100")
+	})
+}
@@ -0,0 +1,226 @@
+package wex
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// parseID parses a key of a Trade API response map as ID.
+func parseID(key string) (int, error) {
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, DecodeError{Err: err}
+	}
+	return id, nil
+}
+
+// inRange reports whether t is within [since, end], with zero bounds ignored.
+func inRange(t Time, since time.Time, end time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (end.IsZero() || !t.After(end))
+}
+
+// item is an element of a Trade API response map keyed by IDs.
+type item interface {
+	itemID() int
+	itemTime() Time
+}
+
+func (o ActiveOrder) itemID() int               { return o.ID }
+func (o ActiveOrder) itemTime() Time            { return o.TimestampCreated }
+func (o OrderInfoItem) itemID() int             { return o.ID }
+func (o OrderInfoItem) itemTime() Time          { return o.TimestampCreated }
+func (t TradeHistoryItem) itemID() int          { return t.ID }
+func (t TradeHistoryItem) itemTime() Time       { return t.Timestamp }
+func (t TransactionHistoryItem) itemID() int    { return t.ID }
+func (t TransactionHistoryItem) itemTime() Time { return t.Timestamp }
+
+// decodeItems decodes data into dst, a pointer to a map of items keyed by IDs, and sets the ID field of the items from the keys.
+func decodeItems(data []byte, dst interface{}) error {
+	m := reflect.ValueOf(dst).Elem()
+	// the unnamed map type has no UnmarshalJSON method calling decodeItems again
+	decoded := reflect.New(reflect.MapOf(m.Type().Key(), m.Type().Elem()))
+	if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+		return err
+	}
+
+	items := reflect.MakeMapWithSize(m.Type(), decoded.Elem().Len())
+	iter := decoded.Elem().MapRange()
+	for iter.Next() {
+		id, err := parseID(iter.Key().String())
+		if err != nil {
+			return err
+		}
+		item := reflect.New(m.Type().Elem()).Elem()
+		item.Set(iter.Value())
+		item.FieldByName("ID").SetInt(int64(id))
+		items.SetMapIndex(iter.Key(), item)
+	}
+	m.Set(items)
+	return nil
+}
+
+// sortedItems returns the items of m, a map of items keyed by IDs, as a slice in ascending order of IDs, or of times and IDs for equal times if byTime is set.
+func sortedItems(m interface{}, byTime bool) interface{} {
+	v := reflect.ValueOf(m)
+	items := reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		items = reflect.Append(items, iter.Value())
+	}
+
+	at := func(i int) item { return items.Index(i).Interface().(item) }
+	sort.Slice(items.Interface(), func(i, j int) bool {
+		a, b := at(i), at(j)
+		if byTime && !a.itemTime().Equal(b.itemTime().Time) {
+			return a.itemTime().Before(b.itemTime().Time)
+		}
+		return a.itemID() < b.itemID()
+	})
+	return items.Interface()
+}
+
+// filterItems returns a map of the same type as m, a map of items keyed by IDs, with the items for which keep returns true.
+func filterItems(m interface{}, keep func(item item) bool) interface{} {
+	v := reflect.ValueOf(m)
+	filtered := reflect.MakeMap(v.Type())
+	iter := v.MapRange()
+	for iter.Next() {
+		if keep(iter.Value().Interface().(item)) {
+			filtered.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	return filtered.Interface()
+}
+
+// between returns a filter of items within [since, end], with zero bounds ignored.
+func between(since time.Time, end time.Time) func(item item) bool {
+	return func(item item) bool { return inRange(item.itemTime(), since, end) }
+}
+
+// UnmarshalJSON decodes the active orders and sets their IDs from the keys.
+func (o *ActiveOrders) UnmarshalJSON(data []byte) error {
+	return decodeItems(data, o)
+}
+
+// Items returns the active orders in ascending order of IDs.
+func (o ActiveOrders) Items() []ActiveOrder {
+	return sortedItems(o, false).([]ActiveOrder)
+}
+
+// ByTime returns the active orders in order of creation.
+func (o ActiveOrders) ByTime() []ActiveOrder {
+	return sortedItems(o, true).([]ActiveOrder)
+}
+
+// ByPair returns the active orders of the pair.
+func (o ActiveOrders) ByPair(pair Pair) ActiveOrders {
+	return filterItems(o, func(item item) bool { return item.(ActiveOrder).Pair == pair }).(ActiveOrders)
+}
+
+// ByType returns the active orders of the type.
+func (o ActiveOrders) ByType(orderType OrderType) ActiveOrders {
+	return filterItems(o, func(item item) bool { return item.(ActiveOrder).Type == orderType }).(ActiveOrders)
+}
+
+// Between returns the active orders created within [since, end]. Zero since or end is not bound.
+func (o ActiveOrders) Between(since time.Time, end time.Time) ActiveOrders {
+	return filterItems(o, between(since, end)).(ActiveOrders)
+}
+
+// UnmarshalJSON decodes the orders and sets their IDs from the keys.
+func (o *OrderInfo) UnmarshalJSON(data []byte) error {
+	return decodeItems(data, o)
+}
+
+// Items returns the orders in ascending order of IDs.
+func (o OrderInfo) Items() []OrderInfoItem {
+	return sortedItems(o, false).([]OrderInfoItem)
+}
+
+// ByTime returns the orders in order of creation.
+func (o OrderInfo) ByTime() []OrderInfoItem {
+	return sortedItems(o, true).([]OrderInfoItem)
+}
+
+// ByPair returns the orders of the pair.
+func (o OrderInfo) ByPair(pair Pair) OrderInfo {
+	return filterItems(o, func(item item) bool { return item.(OrderInfoItem).Pair == pair }).(OrderInfo)
+}
+
+// ByType returns the orders of the type.
+func (o OrderInfo) ByType(orderType OrderType) OrderInfo {
+	return filterItems(o, func(item item) bool { return item.(OrderInfoItem).Type == orderType }).(OrderInfo)
+}
+
+// Between returns the orders created within [since, end]. Zero since or end is not bound.
+func (o OrderInfo) Between(since time.Time, end time.Time) OrderInfo {
+	return filterItems(o, between(since, end)).(OrderInfo)
+}
+
+// UnmarshalJSON decodes the trades and sets their IDs from the keys.
+func (h *TradeHistory) UnmarshalJSON(data []byte) error {
+	return decodeItems(data, h)
+}
+
+// Items returns the trades in ascending order of IDs, which is also chronological.
+func (h TradeHistory) Items() []TradeHistoryItem {
+	return sortedItems(h, false).([]TradeHistoryItem)
+}
+
+// ByTime returns the trades in ascending order of timestamps, and of IDs for equal timestamps.
+func (h TradeHistory) ByTime() []TradeHistoryItem {
+	return sortedItems(h, true).([]TradeHistoryItem)
+}
+
+// ByPair returns the trades of the pair.
+func (h TradeHistory) ByPair(pair Pair) TradeHistory {
+	return filterItems(h, func(item item) bool { return item.(TradeHistoryItem).Pair == pair }).(TradeHistory)
+}
+
+// ByType returns the trades of the type.
+func (h TradeHistory) ByType(orderType OrderType) TradeHistory {
+	return filterItems(h, func(item item) bool { return item.(TradeHistoryItem).Type == orderType }).(TradeHistory)
+}
+
+// ByOrder returns the trades of the order.
+func (h TradeHistory) ByOrder(orderID int) TradeHistory {
+	return filterItems(h, func(item item) bool { return item.(TradeHistoryItem).OrderID == orderID }).(TradeHistory)
+}
+
+// Between returns the trades within [since, end]. Zero since or end is not bound.
+func (h TradeHistory) Between(since time.Time, end time.Time) TradeHistory {
+	return filterItems(h, between(since, end)).(TradeHistory)
+}
+
+// UnmarshalJSON decodes the transactions and sets their IDs from the keys.
+func (h *TransactionHistory) UnmarshalJSON(data []byte) error {
+	return decodeItems(data, h)
+}
+
+// Items returns the transactions in ascending order of IDs, which is also chronological.
+func (h TransactionHistory) Items() []TransactionHistoryItem {
+	return sortedItems(h, false).([]TransactionHistoryItem)
+}
+
+// ByTime returns the transactions in ascending order of timestamps, and of IDs for equal timestamps.
+func (h TransactionHistory) ByTime() []TransactionHistoryItem {
+	return sortedItems(h, true).([]TransactionHistoryItem)
+}
+
+// ByCurrency returns the transactions in the currency.
+func (h TransactionHistory) ByCurrency(currency Currency) TransactionHistory {
+	return filterItems(h, func(item item) bool { return item.(TransactionHistoryItem).Currency == currency }).(TransactionHistory)
+}
+
+// ByType returns the transactions of the type.
+func (h TransactionHistory) ByType(transactionType TransactionType) TransactionHistory {
+	return filterItems(h, func(item item) bool { return item.(TransactionHistoryItem).Type == transactionType }).(TransactionHistory)
+}
+
+// Between returns the transactions within [since, end]. Zero since or end is not bound.
+func (h TransactionHistory) Between(since time.Time, end time.Time) TransactionHistory {
+	return filterItems(h, between(since, end)).(TransactionHistory)
+}
@@ -0,0 +1,89 @@
+package wex
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResults(t *testing.T) {
+
+	Convey("Trade history decoded from a response", t, func() {
+
+		var history TradeHistory
+		err := json.Unmarshal([]byte(`{
+			"12":{"pair":"ltc_usd","type":"sell","amount":2,"rate":50,"order_id":7,"timestamp":1500000010},
+			"3":{"pair":"btc_usd","type":"buy","amount":1,"rate":900,"order_id":5,"timestamp":1500000020},
+			"10":{"pair":"btc_usd","type":"sell","amount":0.5,"rate":910,"order_id":6,"timestamp":1500000030}}`), &history)
+		So(err, ShouldBeNil)
+
+		Convey("Items should carry their IDs", func() {
+			So(history["12"].ID, ShouldEqual, 12)
+		})
+
+		Convey("Items should be encoded without IDs, as in the response", func() {
+			data, err := json.Marshal(history["12"])
+			So(err, ShouldBeNil)
+			So(string(data), ShouldNotContainSubstring, `"id"`)
+
+			var decoded TradeHistory
+			data, err = json.Marshal(history)
+			So(err, ShouldBeNil)
+			So(json.Unmarshal(data, &decoded), ShouldBeNil)
+			So(decoded, ShouldResemble, history)
+		})
+
+		Convey("Items should be sorted by ID or time", func() {
+			items := history.Items()
+			So([]int{items[0].ID, items[1].ID, items[2].ID}, ShouldResemble, []int{3, 10, 12})
+			items = history.ByTime()
+			So([]int{items[0].ID, items[1].ID, items[2].ID}, ShouldResemble, []int{12, 3, 10})
+		})
+
+		Convey("Items should be filtered", func() {
+			So(len(history.ByPair("btc_usd")), ShouldEqual, 2)
+			So(len(history.ByPair("btc_usd").ByType(Sell)), ShouldEqual, 1)
+			So(history.ByOrder(5).Items()[0].ID, ShouldEqual, 3)
+			So(len(history.Between(time.Unix(1500000015, 0), time.Time{})), ShouldEqual, 2)
+			So(len(history.Between(time.Unix(1500000010, 0), time.Unix(1500000020, 0))), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Transaction history, active orders and order info should carry IDs", t, func() {
+
+		var transactions TransactionHistory
+		So(json.Unmarshal([]byte(`{
+			"8":{"type":1,"amount":1,"currency":"BTC","status":2,"timestamp":1500000000},
+			"9":{"type":2,"amount":2,"currency":"USD","status":2,"timestamp":1500000000}}`), &transactions), ShouldBeNil)
+		So(transactions.ByCurrency("USD").Items()[0].ID, ShouldEqual, 9)
+		So(transactions.ByType(TransactionDeposit).Items()[0].ID, ShouldEqual, 8)
+		So(transactions.ByTime()[1].ID, ShouldEqual, 9)
+
+		var orders ActiveOrders
+		So(json.Unmarshal([]byte(`{
+			"43":{"pair":"btc_usd","type":"buy","amount":1,"rate":900,"timestamp_created":1500000010,"status":0},
+			"44":{"pair":"ltc_usd","type":"sell","amount":1,"rate":50,"timestamp_created":1500000000,"status":0}}`), &orders), ShouldBeNil)
+		So(orders.ByTime()[0].ID, ShouldEqual, 44)
+		So(orders.ByPair("btc_usd").ByType(Buy).Items()[0].ID, ShouldEqual, 43)
+		So(orders.Between(time.Time{}, time.Unix(1500000005, 0)).Items()[0].ID, ShouldEqual, 44)
+
+		var info OrderInfo
+		So(json.Unmarshal([]byte(`{
+			"42":{"pair":"btc_usd","type":"buy","start_amount":2,"amount":1,"rate":900,"timestamp_created":1500000010,"status":0},
+			"45":{"pair":"btc_usd","type":"sell","start_amount":1,"amount":0,"rate":950,"timestamp_created":1500000000,"status":1}}`), &info), ShouldBeNil)
+		So(info.Items()[0].ID, ShouldEqual, 42)
+		So(info.ByTime()[0].ID, ShouldEqual, 45)
+		So(len(info.ByPair("btc_usd")), ShouldEqual, 2)
+		So(info.ByPair("btc_usd").ByType(Buy).Items()[0].ID, ShouldEqual, 42)
+		So(info.Between(time.Time{}, time.Unix(1500000005, 0)).Items()[0].ID, ShouldEqual, 45)
+	})
+
+	Convey("Keys which are not IDs should be reported", t, func() {
+		var history TradeHistory
+		err := json.Unmarshal([]byte(`{"abc":{"pair":"btc_usd"}}`), &history)
+		So(errors.Is(err, ErrMalformedResponse), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,76 @@
+package wex
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how read-only requests are retried after transient failures.
+// Requests which change the state of the account, such as Trade, CancelOrder, WithdrawCoin or coupon methods, are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, which doubles with every further retry up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter is the fraction of the delay which is randomized, between 0 and 1.
+	Jitter float64
+	// Retryable reports whether a failed request should be retried. IsRetryable is used if it is nil.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy retries read-only requests up to two times with exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+}
+
+// WithRetryPolicy sets the retry policy of read-only requests of Public and Trade APIs.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = &policy
+	}
+}
+
+// do calls fn until it succeeds, fails with an error which is not retryable, the attempts are exhausted or ctx is done.
+func (p *RetryPolicy) do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if p == nil {
+		return err
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+
+	for attempt := 1; attempt < p.MaxAttempts && err != nil && retryable(err); attempt++ {
+		timer := time.NewTimer(p.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		err = fn()
+	}
+	return err
+}
+
+// delay returns the backoff before the given retry, starting from 1.
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(p.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
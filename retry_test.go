@@ -0,0 +1,88 @@
+package wex
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetryPolicy(t *testing.T) {
+
+	Convey("Server failing with 502 for the first requests", t, func() {
+
+		failures := 0
+		requests := map[string]int{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method := r.URL.Path
+			if r.Method == "POST" {
+				body, _ := ioutil.ReadAll(r.Body)
+				values, _ := url.ParseQuery(string(body))
+				method = values.Get("method")
+			}
+			requests[method]++
+			if requests[method] <= failures {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			if r.Method == "POST" {
+				w.Write([]byte(`{"success":1,"return":{}}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		}))
+		defer server.Close()
+
+		policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+		api := New(WithPublicURL(server.URL), WithTradeURL(server.URL), WithRetryPolicy(policy))
+
+		Convey("Read-only requests should be retried until they succeed", func() {
+			failures = 2
+			_, err := api.Public.Depth([]Pair{"btc_usd"}, 1)
+			So(err, ShouldBeNil)
+			So(requests["/depth/btc_usd"], ShouldEqual, 3)
+
+			_, err = api.Trade.GetInfo()
+			So(err, ShouldBeNil)
+			So(requests["getInfo"], ShouldEqual, 3)
+		})
+
+		Convey("Read-only requests should fail when attempts are exhausted", func() {
+			failures = 5
+			_, err := api.Trade.ActiveOrders("btc_usd")
+			So(errors.Is(err, ErrHTTPStatus), ShouldBeTrue)
+			So(requests["ActiveOrders"], ShouldEqual, 3)
+		})
+
+		Convey("Orders, withdrawals and coupons should never be retried", func() {
+			failures = 1
+			_, err := api.Trade.Trade("btc_usd", "buy", 900, 1)
+			So(err, ShouldNotBeNil)
+			_, err = api.Trade.WithdrawCoin("BTC", 1, "address")
+			So(err, ShouldNotBeNil)
+			_, err = api.Trade.CreateCoupon("BTC", 1)
+			So(err, ShouldNotBeNil)
+			So(requests["Trade"], ShouldEqual, 1)
+			So(requests["WithdrawCoin"], ShouldEqual, 1)
+			So(requests["CreateCoupon"], ShouldEqual, 1)
+		})
+	})
+
+	Convey("Backoff delay should grow exponentially up to the maximum", t, func() {
+		policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+		So(policy.delay(1), ShouldEqual, 100*time.Millisecond)
+		So(policy.delay(2), ShouldEqual, 200*time.Millisecond)
+		So(policy.delay(3), ShouldEqual, 400*time.Millisecond)
+		So(policy.delay(10), ShouldEqual, time.Second)
+
+		policy.Jitter = 0.5
+		for i := 0; i < 10; i++ {
+			So(policy.delay(1), ShouldBeBetweenOrEqual, 50*time.Millisecond, 100*time.Millisecond)
+		}
+	})
+}
@@ -5,51 +5,57 @@ import (
 	"time"
 )
 
-type Ticker map[string]TickerPair
+type Ticker map[Pair]TickerPair
 
 type TickerPair struct {
-	High    float64 `json:"high"`
-	Low     float64 `json:"low"`
-	Avg     float64 `json:"avg"`
-	Vol     float64 `json:"vol"`
-	VolCur  float64 `json:"vol_cur"`
-	Last    float64 `json:"last"`
-	Buy     float64 `json:"buy"`
-	Sell    float64 `json:"sell"`
-	Updated int64   `json:"updated"`
+	High    Decimal `json:"high"`
+	Low     Decimal `json:"low"`
+	Avg     Decimal `json:"avg"`
+	Vol     Decimal `json:"vol"`
+	VolCur  Decimal `json:"vol_cur"`
+	Last    Decimal `json:"last"`
+	Buy     Decimal `json:"buy"`
+	Sell    Decimal `json:"sell"`
+	Updated Time    `json:"updated"`
 }
 
 type Info struct {
-	ServerTime int64               `json:"server_time"`
-	Pairs      map[string]InfoPair `json:"pairs"`
+	ServerTime Time              `json:"server_time"`
+	Pairs      map[Pair]InfoPair `json:"pairs"`
 }
 
 type InfoPair struct {
 	DecimalPlaces int     `json:"decimal_places"`
-	MinPrice      float64 `json:"min_price"`
-	MaxPrice      float64 `json:"max_price"`
-	MinAmount     float64 `json:"min_amount"`
-	Hidden        int     `json:"hidden"`
-	Fee           float64 `json:"fee"`
+	MinPrice      Decimal `json:"min_price"`
+	MaxPrice      Decimal `json:"max_price"`
+	MinAmount     Decimal `json:"min_amount"`
+	Hidden        Flag    `json:"hidden"`
+	Fee           Decimal `json:"fee"`
+
+	// AmountPlaces is the number of decimal places of order amounts. Info responses do not include it, zero means 8 places.
+	AmountPlaces int `json:"-"`
 }
 
-type Depth map[string]DepthPair
+type Depth map[Pair]DepthPair
 
 type DepthPair struct {
 	Asks []DepthItem `json:"asks"`
 	Bids []DepthItem `json:"bids"`
 }
 
-type DepthItem []float64
+type DepthItem struct {
+	Price  Decimal
+	Amount Decimal
+}
 
-type Trades map[string]TradePair
+type Trades map[Pair]TradePair
 type TradePair []TradeItem
 type TradeItem struct {
 	Type      string  `json:"type"`
-	Price     float64 `json:"price"`
-	Amount    float64 `json:"amount"`
+	Price     Decimal `json:"price"`
+	Amount    Decimal `json:"amount"`
 	TID       int64   `json:"tid"`
-	Timestamp int64   `json:"timestamp"`
+	Timestamp Time    `json:"timestamp"`
 }
 
 type Response struct {
@@ -59,52 +65,54 @@ type Response struct {
 }
 
 type AccountInfo struct {
-	Funds            map[string]float64 `json:"funds"`
-	Rights           Rights             `json:"rights"`
-	TransactionCount int64              `json:"transaction_count"`
-	OpenOrders       int64              `json:"open_orders"`
-	ServerTime       float64            `json:"server_time"`
+	Funds            map[Currency]Decimal `json:"funds"`
+	Rights           Rights               `json:"rights"`
+	TransactionCount int64                `json:"transaction_count"`
+	OpenOrders       int64                `json:"open_orders"`
+	ServerTime       Time                 `json:"server_time"`
 }
 
 type Rights struct {
-	Info     int `json:"info"`
-	Trade    int `json:"trade"`
-	Withdraw int `json:"withdraw"`
+	Info     Flag `json:"info"`
+	Trade    Flag `json:"trade"`
+	Withdraw Flag `json:"withdraw"`
 }
 
 type ActiveOrder struct {
-	Pair             string  `json:"pair"`
-	Type             string  `json:"type"`
-	Amount           float64 `json:"amount"`
-	Rate             float64 `json:"rate"`
-	TimestampCreated int64   `json:"timestamp_created"`
-	Status           int     `json:"status"`
+	ID               int         `json:"-"`
+	Pair             Pair        `json:"pair"`
+	Type             OrderType   `json:"type"`
+	Amount           Decimal     `json:"amount"`
+	Rate             Decimal     `json:"rate"`
+	TimestampCreated Time        `json:"timestamp_created"`
+	Status           OrderStatus `json:"status"`
 }
 
 type ActiveOrders map[string]ActiveOrder
 
 type TradeResponse struct {
-	Received float64            `json:"received"`
-	Remains  float64            `json:"remains"`
-	OrderID  int                `json:"order_id"`
-	Funds    map[string]float64 `json:"funds"`
+	Received Decimal              `json:"received"`
+	Remains  Decimal              `json:"remains"`
+	OrderID  int                  `json:"order_id"`
+	Funds    map[Currency]Decimal `json:"funds"`
 }
 
 type OrderInfoItem struct {
-	Pair             string  `json:"pair"`
-	Type             string  `json:"type"`
-	StartAmount      float64 `json:"start_amount"`
-	Amount           float64 `json:"amount"`
-	Rate             float64 `json:"rate"`
-	TimestampCreated int64   `json:"timestamp_created"`
-	Status           int     `json:"status"`
+	ID               int         `json:"-"`
+	Pair             Pair        `json:"pair"`
+	Type             OrderType   `json:"type"`
+	StartAmount      Decimal     `json:"start_amount"`
+	Amount           Decimal     `json:"amount"`
+	Rate             Decimal     `json:"rate"`
+	TimestampCreated Time        `json:"timestamp_created"`
+	Status           OrderStatus `json:"status"`
 }
 
 type OrderInfo map[string]OrderInfoItem
 
 type CancelOrder struct {
-	OrderID int                `json:"order_id"`
-	Funds   map[string]float64 `json:"funds"`
+	OrderID int                  `json:"order_id"`
+	Funds   map[Currency]Decimal `json:"funds"`
 }
 
 type HistoryFilter struct {
@@ -118,43 +126,45 @@ type HistoryFilter struct {
 }
 
 type TradeHistoryItem struct {
-	Pair        string  `json:"pair"`
-	Type        string  `json:"type"`
-	Amount      float64 `json:"amount"`
-	Rate        float64 `json:"rate"`
-	OrderID     int     `json:"order_id"`
-	IsYourOrder int     `json:"is_your_order"`
-	Timestamp   int64   `json:"timestamp"`
+	ID          int       `json:"-"`
+	Pair        Pair      `json:"pair"`
+	Type        OrderType `json:"type"`
+	Amount      Decimal   `json:"amount"`
+	Rate        Decimal   `json:"rate"`
+	OrderID     int       `json:"order_id"`
+	IsYourOrder Flag      `json:"is_your_order"`
+	Timestamp   Time      `json:"timestamp"`
 }
 
 type TradeHistory map[string]TradeHistoryItem
 
 type TransactionHistoryItem struct {
-	Type        int     `json:"type"`
-	Amount      float64 `json:"amount"`
-	Currency    string  `json:"currency"`
-	Description string  `json:"desc"`
-	Status      int     `json:"status"`
-	Timestamp   int64   `json:"timestamp"`
+	ID          int               `json:"-"`
+	Type        TransactionType   `json:"type"`
+	Amount      Decimal           `json:"amount"`
+	Currency    Currency          `json:"currency"`
+	Description string            `json:"desc"`
+	Status      TransactionStatus `json:"status"`
+	Timestamp   Time              `json:"timestamp"`
 }
 
 type TransactionHistory map[string]TransactionHistoryItem
 
 type WithdrawCoin struct {
-	TransactionID int                `json:"tId"`
-	AmountSent    float64            `json:"amountSent"`
-	Funds         map[string]float64 `json:"funds"`
+	TransactionID int                  `json:"tId"`
+	AmountSent    Decimal              `json:"amountSent"`
+	Funds         map[Currency]Decimal `json:"funds"`
 }
 
 type CreateCoupon struct {
-	Coupon        string             `json:"coupon"`
-	TransactionID int                `json:"transID"`
-	Funds         map[string]float64 `json:"funds"`
+	Coupon        string               `json:"coupon"`
+	TransactionID int                  `json:"transID"`
+	Funds         map[Currency]Decimal `json:"funds"`
 }
 
 type RedeemCoupon struct {
-	CouponAmount   string             `json:"couponAmount"`
-	CouponCurrency string             `json:"couponCurrency"`
-	TransactionID  int                `json:"transID"`
-	Funds          map[string]float64 `json:"funds"`
+	CouponAmount   Decimal              `json:"couponAmount"`
+	CouponCurrency Currency             `json:"couponCurrency"`
+	TransactionID  int                  `json:"transID"`
+	Funds          map[Currency]Decimal `json:"funds"`
 }
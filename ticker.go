@@ -0,0 +1,278 @@
+package wex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TickerEvent is a change of Last, Buy or Sell price of a pair received by TickerWatcher.
+// Previous is the zero value for the first ticker of the pair.
+type TickerEvent struct {
+	Pair     Pair
+	Ticker   TickerPair
+	Previous TickerPair
+	First    bool
+	Time     time.Time
+}
+
+// Spread returns the absolute difference between Buy and Sell prices.
+func (e TickerEvent) Spread() Decimal {
+	return e.Ticker.Buy.Sub(e.Ticker.Sell).Abs()
+}
+
+// TickerCondition decides whether a ticker event triggers an alert.
+// Check is called for every event of every pair in chronological order and never concurrently, as polls of a TickerWatcher are serialized, so conditions may keep state between events.
+type TickerCondition interface {
+	Check(event TickerEvent) bool
+}
+
+// TickerConditionFunc adapts a function to TickerCondition.
+type TickerConditionFunc func(event TickerEvent) bool
+
+// Check calls f(event).
+func (f TickerConditionFunc) Check(event TickerEvent) bool {
+	return f(event)
+}
+
+// PriceCrosses returns a condition triggered when the last price of the pair crosses level in either direction, i.e. the previous price was below and the current price is at or above level or vice versa.
+func PriceCrosses(pair Pair, level Decimal) TickerCondition {
+	return TickerConditionFunc(func(event TickerEvent) bool {
+		if event.Pair != pair || event.First {
+			return false
+		}
+		before, after := event.Previous.Last.Cmp(level), event.Ticker.Last.Cmp(level)
+		return (before < 0 && after >= 0) || (before >= 0 && after < 0)
+	})
+}
+
+// SpreadWider returns a condition triggered when the spread between Buy and Sell prices of the pair becomes wider than spread.
+// It is triggered again only after the spread narrowed to spread or less.
+func SpreadWider(pair Pair, spread Decimal) TickerCondition {
+	wide := false
+	return TickerConditionFunc(func(event TickerEvent) bool {
+		if event.Pair != pair {
+			return false
+		}
+		wasWide := wide
+		wide = event.Spread().Cmp(spread) > 0
+		return wide && !wasWide
+	})
+}
+
+type priceSample struct {
+	time  time.Time
+	price Decimal
+}
+
+// PercentMove returns a condition triggered when the last price of the pair moved by percent or more, in either direction, within window.
+// After it is triggered, moves are measured from the price that triggered it.
+func PercentMove(pair Pair, percent Decimal, window time.Duration) TickerCondition {
+	threshold := percent.Div(NewDecimalFromInt(100))
+	var samples []priceSample
+	return TickerConditionFunc(func(event TickerEvent) bool {
+		if event.Pair != pair {
+			return false
+		}
+		price := event.Ticker.Last
+
+		// keep the last sample before the window as the price at the start of the window
+		start := event.Time.Add(-window)
+		i := 0
+		for i+1 < len(samples) && !samples[i+1].time.After(start) {
+			i++
+		}
+		samples = samples[i:]
+
+		triggered := false
+		for _, sample := range samples {
+			if sample.price.IsZero() {
+				continue
+			}
+			if price.Sub(sample.price).Abs().Div(sample.price.Abs()).Cmp(threshold) >= 0 {
+				triggered = true
+				break
+			}
+		}
+
+		if triggered {
+			samples = samples[:0]
+		}
+		samples = append(samples, priceSample{time: event.Time, price: price})
+		return triggered
+	})
+}
+
+type tickerAlert struct {
+	condition TickerCondition
+	fn        func(event TickerEvent)
+	ch        chan TickerEvent
+}
+
+// TickerWatcher polls PublicAPI.Ticker for pairs and reports changes of Last, Buy or Sell prices to subscribers and alerts.
+// Its methods are safe for concurrent use. Concurrent polls compute and deliver their changes one after another, so alert conditions are never checked concurrently.
+type TickerWatcher struct {
+	// OnError is called with errors of Ticker requests. Polling continues after errors.
+	OnError func(err error)
+
+	api      *PublicAPI
+	pairs    []Pair
+	interval time.Duration
+
+	// delivery serializes polls from computing the changes until they are delivered, so that conditions are checked in order and channels are not closed during a delivery.
+	delivery    sync.Mutex
+	mu          sync.Mutex
+	last        map[Pair]TickerPair
+	subscribers []chan TickerEvent
+	alerts      []*tickerAlert
+	closed      bool
+}
+
+// NewTickerWatcher creates a TickerWatcher polling tickers of the pairs every interval.
+func NewTickerWatcher(api *PublicAPI, pairs []Pair, interval time.Duration) *TickerWatcher {
+	return &TickerWatcher{
+		api:      api,
+		pairs:    pairs,
+		interval: interval,
+		last:     make(map[Pair]TickerPair, len(pairs)),
+	}
+}
+
+// Subscribe returns a channel receiving every change, buffered by buffer events.
+// The first poll reports the ticker of every pair. The channel is closed when Run returns, or immediately if Run has returned already.
+func (w *TickerWatcher) Subscribe(buffer int) <-chan TickerEvent {
+	ch := make(chan TickerEvent, buffer)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		close(ch)
+		return ch
+	}
+	w.subscribers = append(w.subscribers, ch)
+	return ch
+}
+
+// Alert calls fn with the events triggering condition. fn is called from the polling goroutine and delays polling until it returns.
+func (w *TickerWatcher) Alert(condition TickerCondition, fn func(event TickerEvent)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.alerts = append(w.alerts, &tickerAlert{condition: condition, fn: fn})
+}
+
+// AlertChan returns a channel receiving the events triggering condition, buffered by buffer events.
+// The channel is closed when Run returns, or immediately if Run has returned already.
+func (w *TickerWatcher) AlertChan(condition TickerCondition, buffer int) <-chan TickerEvent {
+	ch := make(chan TickerEvent, buffer)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		close(ch)
+		return ch
+	}
+	w.alerts = append(w.alerts, &tickerAlert{condition: condition, ch: ch})
+	return ch
+}
+
+// Run polls the tickers until ctx is done and returns the context error.
+func (w *TickerWatcher) Run(ctx context.Context) error {
+	defer w.closeSubscribers()
+	return poll(ctx, w.interval, w.Poll, w.OnError)
+}
+
+// Poll requests the tickers once and delivers the changes to subscribers and alerts.
+// Poll may be called while Run is running. After Run has returned, Poll only updates the last tickers and calls callback alerts.
+func (w *TickerWatcher) Poll(ctx context.Context) error {
+	ticker, err := w.api.TickerContext(ctx, w.pairs)
+	if err != nil {
+		return err
+	}
+
+	w.delivery.Lock()
+	defer w.delivery.Unlock()
+
+	now := time.Now()
+	var events []TickerEvent
+
+	w.mu.Lock()
+	for _, pair := range w.pairs {
+		current, ok := ticker[pair]
+		if !ok {
+			continue
+		}
+		previous, seen := w.last[pair]
+		w.last[pair] = current
+		if seen && previous.Last.Equal(current.Last) && previous.Buy.Equal(current.Buy) && previous.Sell.Equal(current.Sell) {
+			continue
+		}
+		events = append(events, TickerEvent{Pair: pair, Ticker: current, Previous: previous, First: !seen, Time: now})
+	}
+	subscribers := append([]chan TickerEvent(nil), w.subscribers...)
+	alerts := append([]*tickerAlert(nil), w.alerts...)
+	w.mu.Unlock()
+
+	for _, event := range events {
+		for _, ch := range subscribers {
+			if err := sendTickerEvent(ctx, ch, event); err != nil {
+				return err
+			}
+		}
+		for _, alert := range alerts {
+			if !alert.condition.Check(event) {
+				continue
+			}
+			if alert.fn != nil {
+				alert.fn(event)
+				continue
+			}
+			if err := sendTickerEvent(ctx, alert.ch, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Last returns the last ticker of the pair, or false if it was not received yet.
+func (w *TickerWatcher) Last(pair Pair) (TickerPair, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ticker, ok := w.last[pair]
+	return ticker, ok
+}
+
+func sendTickerEvent(ctx context.Context, ch chan TickerEvent, event TickerEvent) error {
+	select {
+	case ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeSubscribers closes the channels of subscribers and channel alerts after a delivery in progress. Callback alerts are kept.
+func (w *TickerWatcher) closeSubscribers() {
+	w.delivery.Lock()
+	defer w.delivery.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for _, ch := range w.subscribers {
+		close(ch)
+	}
+	w.subscribers = nil
+
+	callbacks := w.alerts[:0]
+	for _, alert := range w.alerts {
+		if alert.ch != nil {
+			close(alert.ch)
+			continue
+		}
+		callbacks = append(callbacks, alert)
+	}
+	w.alerts = callbacks
+}
@@ -0,0 +1,112 @@
+package wex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTickerWatcher(t *testing.T) {
+
+	Convey("Ticker watcher polling a server", t, func() {
+
+		var mu sync.Mutex
+		body := `{"btc_usd":{"last":100,"buy":101,"sell":99,"vol":10,"updated":1500000000}}`
+		set := func(b string) {
+			mu.Lock()
+			defer mu.Unlock()
+			body = b
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		api := NewPublicAPI(WithPublicURL(server.URL))
+		watcher := NewTickerWatcher(api, []Pair{"btc_usd"}, time.Hour)
+		events := watcher.Subscribe(16)
+		crosses := watcher.AlertChan(PriceCrosses("btc_usd", NewDecimalFromInt(105)), 16)
+		var spreads []TickerEvent
+		watcher.Alert(SpreadWider("btc_usd", NewDecimalFromInt(5)), func(event TickerEvent) { spreads = append(spreads, event) })
+
+		So(watcher.Poll(context.Background()), ShouldBeNil)
+		first := <-events
+		So(first.First, ShouldBeTrue)
+		So(first.Ticker.Last.String(), ShouldEqual, "100")
+
+		Convey("Unchanged prices should not be reported", func() {
+			set(`{"btc_usd":{"last":100,"buy":101,"sell":99,"vol":12,"updated":1500000010}}`)
+			So(watcher.Poll(context.Background()), ShouldBeNil)
+			So(len(events), ShouldEqual, 0)
+		})
+
+		Convey("Changed prices should trigger matching alerts", func() {
+			set(`{"btc_usd":{"last":106,"buy":110,"sell":102,"updated":1500000010}}`)
+			So(watcher.Poll(context.Background()), ShouldBeNil)
+
+			event := <-events
+			So(event.Previous.Last.String(), ShouldEqual, "100")
+			So(event.Ticker.Last.String(), ShouldEqual, "106")
+			So(len(crosses), ShouldEqual, 1)
+			So(len(spreads), ShouldEqual, 1)
+			So(spreads[0].Spread().String(), ShouldEqual, "8")
+
+			set(`{"btc_usd":{"last":107,"buy":111,"sell":103,"updated":1500000020}}`)
+			So(watcher.Poll(context.Background()), ShouldBeNil)
+			So(len(crosses), ShouldEqual, 1)
+			So(len(spreads), ShouldEqual, 1)
+		})
+
+		Convey("Concurrent polls should check stateful conditions one at a time", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					watcher.Poll(context.Background())
+				}()
+			}
+			wg.Wait()
+			So(len(spreads), ShouldEqual, 0)
+		})
+
+		Convey("Run should close channels but keep callback alerts", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			So(watcher.Run(ctx), ShouldEqual, context.Canceled)
+			for range events {
+			}
+			_, ok := <-watcher.AlertChan(PriceCrosses("btc_usd", NewDecimalFromInt(1)), 1)
+			So(ok, ShouldBeFalse)
+
+			set(`{"btc_usd":{"last":106,"buy":110,"sell":102,"updated":1500000010}}`)
+			So(watcher.Poll(context.Background()), ShouldBeNil)
+			So(len(spreads), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Percent move should be measured within the window", t, func() {
+
+		move := PercentMove("btc_usd", NewDecimalFromInt(5), time.Minute)
+		start := time.Unix(1500000000, 0)
+		event := func(last string, after time.Duration) TickerEvent {
+			return TickerEvent{Pair: "btc_usd", Ticker: TickerPair{Last: MustParseDecimal(last)}, Time: start.Add(after)}
+		}
+
+		So(move.Check(event("100", 0)), ShouldBeFalse)
+		So(move.Check(event("103", 30*time.Second)), ShouldBeFalse)
+		So(move.Check(event("105", 50*time.Second)), ShouldBeTrue)
+		So(move.Check(event("106", 55*time.Second)), ShouldBeFalse)
+		So(move.Check(event("108", 3*time.Minute)), ShouldBeFalse)
+		So(move.Check(event("102", 3*time.Minute+10*time.Second)), ShouldBeTrue)
+		So(move.Check(TickerEvent{Pair: "ltc_usd", Ticker: TickerPair{Last: NewDecimalFromInt(1)}}), ShouldBeFalse)
+	})
+}
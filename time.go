@@ -0,0 +1,63 @@
+package wex
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Time is a point in time sent by the exchange as Unix seconds.
+// Zero Unix seconds are decoded as the zero time and vice versa.
+type Time struct {
+	time.Time
+}
+
+// NewTime returns t as Time.
+func NewTime(t time.Time) Time {
+	return Time{t}
+}
+
+// UnixTime returns the Time of the given Unix seconds.
+func UnixTime(sec int64) Time {
+	if sec == 0 {
+		return Time{}
+	}
+	return Time{time.Unix(sec, 0)}
+}
+
+// MarshalJSON encodes t as Unix seconds.
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalJSON decodes t from Unix seconds, which may have a fractional part.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+
+	if sec, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		*t = UnixTime(sec)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	sec, frac := math.Modf(f)
+	if f == 0 {
+		*t = Time{}
+		return nil
+	}
+	*t = Time{time.Unix(int64(sec), int64(frac*1e9))}
+	return nil
+}
@@ -0,0 +1,39 @@
+package wex
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTime(t *testing.T) {
+
+	Convey("Timestamps should be decoded from Unix seconds", t, func() {
+		var v struct {
+			Ticker  TickerPair
+			Account AccountInfo
+			Order   ActiveOrder
+		}
+		err := json.Unmarshal([]byte(`{
+			"Ticker":{"updated":1500000000},
+			"Account":{"server_time":1500000000.5},
+			"Order":{"timestamp_created":0}}`), &v)
+		So(err, ShouldBeNil)
+		So(v.Ticker.Updated.Equal(time.Unix(1500000000, 0)), ShouldBeTrue)
+		So(v.Account.ServerTime.Sub(v.Ticker.Updated.Time), ShouldEqual, 500*time.Millisecond)
+		So(v.Order.TimestampCreated.IsZero(), ShouldBeTrue)
+	})
+
+	Convey("Timestamps should be encoded as Unix seconds", t, func() {
+		data, err := json.Marshal([]Time{UnixTime(1500000000), {}})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, `[1500000000,0]`)
+	})
+
+	Convey("Invalid timestamps should not be decoded", t, func() {
+		var ts Time
+		So(json.Unmarshal([]byte(`"yesterday"`), &ts), ShouldNotBeNil)
+	})
+}
@@ -2,6 +2,7 @@ package wex
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
@@ -11,7 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
+	"sync"
 )
 
 // TradeAPI allows to trade on the exchange and receive information about the account.
@@ -19,16 +20,44 @@ import (
 // To use this API, you need to create an API key. An API key can be created in your Profile in the API Keys section. After creating an API key you’ll receive a key and a secret.
 // Note that the Secret can be received only during the first hour after the creation of the Key.
 // API key information is used for authentication.
+//
+// TradeAPI is safe for concurrent use by multiple goroutines: requests are signed and sent one at a time, each after the response to the previous one,
+// so that they reach the server in nonce order. A slow request therefore delays all other requests of the TradeAPI.
+// API key and secret should be changed with Auth rather than by setting the fields while requests are in flight.
 type TradeAPI struct {
 	API_KEY    string
 	API_SECRET string
-	lastNonce  int64
+	client     *Client
+	state      *tradeState
+}
+
+// tradeState is the state shared by copies of a TradeAPI. It is kept behind a pointer so that TradeAPI and API can be copied.
+type tradeState struct {
+	// mu serializes authentication changes, nonce allocation and sending of requests.
+	mu        sync.Mutex
+	lastNonce int64
+}
+
+// tradeStateMu guards the creation of the state of zero value TradeAPIs.
+var tradeStateMu sync.Mutex
+
+// shared returns the state of the TradeAPI, creating it on first use.
+func (tapi *TradeAPI) shared() *tradeState {
+	tradeStateMu.Lock()
+	defer tradeStateMu.Unlock()
+	if tapi.state == nil {
+		tapi.state = &tradeState{}
+	}
+	return tapi.state
 }
 
 const tradeURL = "https://wex.nz/tapi"
 
 // Auth provides API key and secret setting for Trade API
 func (tapi *TradeAPI) Auth(key string, secret string) {
+	state := tapi.shared()
+	state.mu.Lock()
+	defer state.mu.Unlock()
 	tapi.API_KEY = key
 	tapi.API_SECRET = secret
 }
@@ -36,8 +65,13 @@ func (tapi *TradeAPI) Auth(key string, secret string) {
 // GetInfo returns information about the user’s current balance, API-key privileges, the number of open orders and Server Time.
 // To use this method you need a privilege of the key info.
 func (tapi *TradeAPI) GetInfo() (AccountInfo, error) {
+	return tapi.GetInfoContext(context.Background())
+}
+
+// GetInfoContext provides GetInfo capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) GetInfoContext(ctx context.Context) (AccountInfo, error) {
 	info := AccountInfo{}
-	err := tapi.call("getInfo", &info, nil)
+	err := tapi.call(ctx, "getInfo", &info, nil)
 	if err == nil {
 		return info, nil
 	}
@@ -57,17 +91,42 @@ func (tapi *TradeAPI) GetInfoAuth(key string, secret string) (AccountInfo, error
 // You can only create limit orders using this method, but you can emulate market orders using rate parameters. E.g. using rate=0.1 you can sell at the best market price.
 //
 // Each pair has a different limit on the minimum / maximum amounts, the minimum amount and the number of digits after the decimal point. All limitations can be obtained using the info method in PublicAPI.
-func (tapi *TradeAPI) Trade(pair string, orderType string, rate float64, amount float64) (TradeResponse, error) {
+func (tapi *TradeAPI) Trade(pair Pair, orderType OrderType, rate float64, amount float64) (TradeResponse, error) {
+	return tapi.TradeContext(context.Background(), pair, orderType, rate, amount)
+}
+
+// TradeContext provides Trade capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) TradeContext(ctx context.Context, pair Pair, orderType OrderType, rate float64, amount float64) (TradeResponse, error) {
+	return tapi.TradeOrderContext(ctx, Order{Pair: pair, Type: orderType, Rate: NewDecimalFromFloat(rate), Amount: NewDecimalFromFloat(amount)})
+}
+
+// TradeOrder provides Trade capability with the exact decimal rate and amount of the order.
+func (tapi *TradeAPI) TradeOrder(order Order) (TradeResponse, error) {
+	return tapi.TradeOrderContext(context.Background(), order)
+}
+
+// TradeOrderContext provides TradeOrder capability with a context for cancellation and deadlines.
+// If order validation is enabled with WithOrderValidation, the order is normalized before it is sent.
+func (tapi *TradeAPI) TradeOrderContext(ctx context.Context, order Order) (TradeResponse, error) {
+
+	order, err := tapi.validateOrder(ctx, order)
+	if err != nil {
+		return TradeResponse{}, err
+	}
+	return tapi.trade(ctx, order)
+}
+
+func (tapi *TradeAPI) trade(ctx context.Context, order Order) (TradeResponse, error) {
 
 	tradeResponse := TradeResponse{}
 
 	orderParams := make(map[string]string, 4)
-	orderParams["pair"] = pair
-	orderParams["type"] = orderType
-	orderParams["rate"] = strconv.FormatFloat(rate, 'f', -1, 64)
-	orderParams["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	orderParams["pair"] = string(order.Pair)
+	orderParams["type"] = string(order.Type)
+	orderParams["rate"] = order.Rate.String()
+	orderParams["amount"] = order.Amount.String()
 
-	err := tapi.call("Trade", &tradeResponse, orderParams)
+	err := tapi.call(ctx, "Trade", &tradeResponse, orderParams)
 
 	if err == nil {
 		return tradeResponse, nil
@@ -76,7 +135,7 @@ func (tapi *TradeAPI) Trade(pair string, orderType string, rate float64, amount
 }
 
 // TradeAuth provides Trade capability with authorization
-func (tapi *TradeAPI) TradeAuth(key string, secret string, pair string, orderType string, rate float64, amount float64) (TradeResponse, error) {
+func (tapi *TradeAPI) TradeAuth(key string, secret string, pair Pair, orderType OrderType, rate float64, amount float64) (TradeResponse, error) {
 	tapi.Auth(key, secret)
 	return tapi.Trade(pair, orderType, rate, amount)
 
@@ -84,13 +143,20 @@ func (tapi *TradeAPI) TradeAuth(key string, secret string, pair string, orderTyp
 
 // ActiveOrders returns the list of your active orders.  To use this method you need a privilege of the info key.
 // If the order disappears from the list, it was either executed or canceled.
-func (tapi *TradeAPI) ActiveOrders(pair string) (ActiveOrders, error) {
+func (tapi *TradeAPI) ActiveOrders(pair Pair) (ActiveOrders, error) {
+	return tapi.ActiveOrdersContext(context.Background(), pair)
+}
 
-	orderParams := make(map[string]string, 4)
-	orderParams["pair"] = pair
+// ActiveOrdersContext provides ActiveOrders capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) ActiveOrdersContext(ctx context.Context, pair Pair) (ActiveOrders, error) {
+
+	orderParams := make(map[string]string, 1)
+	if pair != "" {
+		orderParams["pair"] = string(pair)
+	}
 
 	activeOrders := make(ActiveOrders, 0)
-	err := tapi.call("ActiveOrders", &activeOrders, orderParams)
+	err := tapi.call(ctx, "ActiveOrders", &activeOrders, orderParams)
 	if err == nil {
 		return activeOrders, nil
 	}
@@ -98,20 +164,25 @@ func (tapi *TradeAPI) ActiveOrders(pair string) (ActiveOrders, error) {
 }
 
 // ActiveOrdersAuth provides ActiveOrders capability with authorization
-func (tapi *TradeAPI) ActiveOrdersAuth(key string, secret string, pair string) (ActiveOrders, error) {
+func (tapi *TradeAPI) ActiveOrdersAuth(key string, secret string, pair Pair) (ActiveOrders, error) {
 	tapi.Auth(key, secret)
 	return tapi.ActiveOrders(pair)
 }
 
 // OrderInfo provides the information on particular order. To use this method you need a privilege of the info key.
 func (tapi *TradeAPI) OrderInfo(orderID string) (OrderInfo, error) {
+	return tapi.OrderInfoContext(context.Background(), orderID)
+}
+
+// OrderInfoContext provides OrderInfo capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) OrderInfoContext(ctx context.Context, orderID string) (OrderInfo, error) {
 
 	orderInfo := OrderInfo{}
 
 	orderParams := make(map[string]string, 1)
 	orderParams["order_id"] = orderID
 
-	err := tapi.call("OrderInfo", &orderInfo, orderParams)
+	err := tapi.call(ctx, "OrderInfo", &orderInfo, orderParams)
 	if err == nil {
 		return orderInfo, nil
 	}
@@ -127,13 +198,18 @@ func (tapi *TradeAPI) OrderInfoAuth(key string, secret string, orderID string) (
 
 // CancelOrder provides method used for order cancellation. To use this method you need a privilege of the trade key.
 func (tapi *TradeAPI) CancelOrder(orderID string) (CancelOrder, error) {
+	return tapi.CancelOrderContext(context.Background(), orderID)
+}
+
+// CancelOrderContext provides CancelOrder capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) CancelOrderContext(ctx context.Context, orderID string) (CancelOrder, error) {
 
 	cancelReponse := CancelOrder{}
 
 	orderParams := make(map[string]string, 1)
 	orderParams["order_id"] = orderID
 
-	err := tapi.call("CancelOrder", &cancelReponse, orderParams)
+	err := tapi.call(ctx, "CancelOrder", &cancelReponse, orderParams)
 
 	if err == nil {
 		return cancelReponse, nil
@@ -149,16 +225,21 @@ func (tapi *TradeAPI) CancelOrderAuth(key string, secret string, orderID string)
 }
 
 // TradeHistory returns trade history. To use this method you need a privilege of the info key.
-func (tapi *TradeAPI) TradeHistory(filter HistoryFilter, pair string) (TradeHistory, error) {
+func (tapi *TradeAPI) TradeHistory(filter HistoryFilter, pair Pair) (TradeHistory, error) {
+	return tapi.TradeHistoryContext(context.Background(), filter, pair)
+}
+
+// TradeHistoryContext provides TradeHistory capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) TradeHistoryContext(ctx context.Context, filter HistoryFilter, pair Pair) (TradeHistory, error) {
 
 	tradeHistory := TradeHistory{}
 
 	historyParams := historyFilterParams(filter)
 	if pair != "" {
-		historyParams["pair"] = pair
+		historyParams["pair"] = string(pair)
 	}
 
-	err := tapi.call("TradeHistory", &tradeHistory, historyParams)
+	err := tapi.call(ctx, "TradeHistory", &tradeHistory, historyParams)
 
 	if err == nil {
 		return tradeHistory, nil
@@ -167,7 +248,7 @@ func (tapi *TradeAPI) TradeHistory(filter HistoryFilter, pair string) (TradeHist
 }
 
 // TradeHistoryAuth provides TradeHistory capability with authorization
-func (tapi *TradeAPI) TradeHistoryAuth(key string, secret string, filter HistoryFilter, pair string) (TradeHistory, error) {
+func (tapi *TradeAPI) TradeHistoryAuth(key string, secret string, filter HistoryFilter, pair Pair) (TradeHistory, error) {
 	tapi.Auth(key, secret)
 	return tapi.TradeHistory(filter, pair)
 
@@ -175,12 +256,17 @@ func (tapi *TradeAPI) TradeHistoryAuth(key string, secret string, filter History
 
 // TransactionHistory returns the history of transactions. To use this method you need a privilege of the info key.
 func (tapi *TradeAPI) TransactionHistory(filter HistoryFilter) (TransactionHistory, error) {
+	return tapi.TransactionHistoryContext(context.Background(), filter)
+}
+
+// TransactionHistoryContext provides TransactionHistory capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) TransactionHistoryContext(ctx context.Context, filter HistoryFilter) (TransactionHistory, error) {
 
 	transactionHistory := TransactionHistory{}
 
 	historyParams := historyFilterParams(filter)
 
-	err := tapi.call("TransHistory", &transactionHistory, historyParams)
+	err := tapi.call(ctx, "TransHistory", &transactionHistory, historyParams)
 
 	if err == nil {
 		return transactionHistory, nil
@@ -196,16 +282,31 @@ func (tapi *TradeAPI) TransactionHistoryAuth(key string, secret string, filter H
 }
 
 // WithdrawCoin provides cryptocurrency withdrawals. You need to have the privilege of the Withdraw key to be able to use this method.
-func (tapi *TradeAPI) WithdrawCoin(coinName string, amount float64, address string) (WithdrawCoin, error) {
+func (tapi *TradeAPI) WithdrawCoin(coinName Currency, amount float64, address string) (WithdrawCoin, error) {
+	return tapi.WithdrawCoinContext(context.Background(), coinName, amount, address)
+}
+
+// WithdrawCoinContext provides WithdrawCoin capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) WithdrawCoinContext(ctx context.Context, coinName Currency, amount float64, address string) (WithdrawCoin, error) {
+	return tapi.WithdrawCoinDecimalContext(ctx, coinName, NewDecimalFromFloat(amount), address)
+}
+
+// WithdrawCoinDecimal provides WithdrawCoin capability with the exact decimal amount.
+func (tapi *TradeAPI) WithdrawCoinDecimal(coinName Currency, amount Decimal, address string) (WithdrawCoin, error) {
+	return tapi.WithdrawCoinDecimalContext(context.Background(), coinName, amount, address)
+}
+
+// WithdrawCoinDecimalContext provides WithdrawCoinDecimal capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) WithdrawCoinDecimalContext(ctx context.Context, coinName Currency, amount Decimal, address string) (WithdrawCoin, error) {
 
 	response := WithdrawCoin{}
 
 	orderParams := make(map[string]string, 3)
-	orderParams["coinName"] = coinName
-	orderParams["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	orderParams["coinName"] = string(coinName)
+	orderParams["amount"] = amount.String()
 	orderParams["address"] = address
 
-	err := tapi.call("WithdrawCoin", &response, orderParams)
+	err := tapi.call(ctx, "WithdrawCoin", &response, orderParams)
 
 	if err == nil {
 		return response, nil
@@ -214,22 +315,37 @@ func (tapi *TradeAPI) WithdrawCoin(coinName string, amount float64, address stri
 }
 
 // WithdrawCoinAuth provides WithdrawCoin capability with authorization
-func (tapi *TradeAPI) WithdrawCoinAuth(key string, secret string, coinName string, amount float64, address string) (WithdrawCoin, error) {
+func (tapi *TradeAPI) WithdrawCoinAuth(key string, secret string, coinName Currency, amount float64, address string) (WithdrawCoin, error) {
 	tapi.Auth(key, secret)
 	return tapi.WithdrawCoin(coinName, amount, address)
 
 }
 
 // CreateCoupon allows you to create Coupons. In order to use this method, you need the Coupon key privilege.
-func (tapi *TradeAPI) CreateCoupon(currency string, amount float64) (CreateCoupon, error) {
+func (tapi *TradeAPI) CreateCoupon(currency Currency, amount float64) (CreateCoupon, error) {
+	return tapi.CreateCouponContext(context.Background(), currency, amount)
+}
+
+// CreateCouponContext provides CreateCoupon capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) CreateCouponContext(ctx context.Context, currency Currency, amount float64) (CreateCoupon, error) {
+	return tapi.CreateCouponDecimalContext(ctx, currency, NewDecimalFromFloat(amount))
+}
+
+// CreateCouponDecimal provides CreateCoupon capability with the exact decimal amount.
+func (tapi *TradeAPI) CreateCouponDecimal(currency Currency, amount Decimal) (CreateCoupon, error) {
+	return tapi.CreateCouponDecimalContext(context.Background(), currency, amount)
+}
+
+// CreateCouponDecimalContext provides CreateCouponDecimal capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) CreateCouponDecimalContext(ctx context.Context, currency Currency, amount Decimal) (CreateCoupon, error) {
 
 	response := CreateCoupon{}
 
 	params := make(map[string]string, 2)
-	params["currency"] = currency
-	params["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+	params["currency"] = string(currency)
+	params["amount"] = amount.String()
 
-	err := tapi.call("CreateCoupon", &response, params)
+	err := tapi.call(ctx, "CreateCoupon", &response, params)
 
 	if err == nil {
 		return response, nil
@@ -238,20 +354,25 @@ func (tapi *TradeAPI) CreateCoupon(currency string, amount float64) (CreateCoupo
 }
 
 // CreateCouponAuth provides CreateCoupon capability with authorization
-func (tapi *TradeAPI) CreateCouponAuth(key string, secret string, currency string, amount float64) (CreateCoupon, error) {
+func (tapi *TradeAPI) CreateCouponAuth(key string, secret string, currency Currency, amount float64) (CreateCoupon, error) {
 	tapi.Auth(key, secret)
 	return tapi.CreateCoupon(currency, amount)
 }
 
 // RedeemCoupon method is used to redeem coupons. In order to use this method, you need the Coupon key privilege.
 func (tapi *TradeAPI) RedeemCoupon(coupon string) (RedeemCoupon, error) {
+	return tapi.RedeemCouponContext(context.Background(), coupon)
+}
+
+// RedeemCouponContext provides RedeemCoupon capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) RedeemCouponContext(ctx context.Context, coupon string) (RedeemCoupon, error) {
 
 	response := RedeemCoupon{}
 
 	params := make(map[string]string, 1)
 	params["coupon"] = coupon
 
-	err := tapi.call("RedeemCoupon", &response, params)
+	err := tapi.call(ctx, "RedeemCoupon", &response, params)
 
 	if err == nil {
 		return response, nil
@@ -265,12 +386,11 @@ func (tapi *TradeAPI) RedeemCouponAuth(key string, secret string, coupon string)
 	return tapi.RedeemCoupon(coupon)
 }
 
-func (tapi *TradeAPI) encodePostData(method string, params map[string]string) string {
-	nonce := time.Now().Unix()
-	if nonce <= tapi.lastNonce {
-		nonce = tapi.lastNonce + 1
+func (tapi *TradeAPI) encodePostData(method string, params map[string]string) (string, error) {
+	nonce, err := tapi.nonce()
+	if err != nil {
+		return "", err
 	}
-	tapi.lastNonce = nonce
 
 	result := fmt.Sprintf("method=%s&nonce=%d", method, nonce)
 
@@ -281,7 +401,32 @@ func (tapi *TradeAPI) encodePostData(method string, params map[string]string) st
 		}
 		result = result + "&" + v.Encode()
 	}
-	return result
+	return result, nil
+}
+
+// nonce returns the nonce for the next request from the configured NonceSource or the in-memory last nonce.
+func (tapi *TradeAPI) nonce() (int64, error) {
+	if source := clientOrDefault(tapi.client).NonceSource; source != nil {
+		return source.Next()
+	}
+	state := tapi.shared()
+	state.lastNonce = nextNonce(state.lastNonce)
+	return state.lastNonce, nil
+}
+
+// syncNonce makes the next nonces greater than the last nonce accepted by the server.
+func (tapi *TradeAPI) syncNonce(last int64) error {
+	state := tapi.shared()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if source := clientOrDefault(tapi.client).NonceSource; source != nil {
+		return source.Sync(last)
+	}
+	if last > state.lastNonce {
+		state.lastNonce = last
+	}
+	return nil
 }
 
 func sign(secret string, payload string) string {
@@ -290,23 +435,41 @@ func sign(secret string, payload string) string {
 	return hex.EncodeToString(mac.Sum(nil))
 }
 
-func (tapi *TradeAPI) call(method string, v interface{}, params map[string]string) error {
+// idempotentMethods are the Trade API methods which do not change the state of the account and can be retried safely.
+var idempotentMethods = map[string]bool{
+	"getInfo":      true,
+	"ActiveOrders": true,
+	"OrderInfo":    true,
+	"TradeHistory": true,
+	"TransHistory": true,
+}
 
-	postData := tapi.encodePostData(method, params)
+// call sends the request, retrying idempotent methods according to the retry policy.
+func (tapi *TradeAPI) call(ctx context.Context, method string, v interface{}, params map[string]string) error {
+	if !idempotentMethods[method] {
+		return tapi.callOnce(ctx, method, v, params)
+	}
+	return clientOrDefault(tapi.client).RetryPolicy.do(ctx, func() error {
+		return tapi.callOnce(ctx, method, v, params)
+	})
+}
 
-	req, err := http.NewRequest("POST", tradeURL, bytes.NewBufferString(postData))
+// callOnce sends the request and sends it once more with a resynchronized nonce if the server rejects the nonce.
+func (tapi *TradeAPI) callOnce(ctx context.Context, method string, v interface{}, params map[string]string) error {
 
-	if err != nil {
-		return err
+	err := tapi.send(ctx, method, v, params)
+	if last, ok := invalidNonce(err); ok {
+		if err := tapi.syncNonce(last); err != nil {
+			return notSentError{err}
+		}
+		err = tapi.send(ctx, method, v, params)
 	}
+	return err
+}
 
-	req.Header.Add("Key", tapi.API_KEY)
-	req.Header.Add("Sign", sign(tapi.API_SECRET, postData))
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Add("Content-Length", strconv.Itoa(len(postData)))
+func (tapi *TradeAPI) send(ctx context.Context, method string, v interface{}, params map[string]string) error {
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := tapi.post(ctx, method, params)
 
 	if err != nil {
 		return err
@@ -315,6 +478,53 @@ func (tapi *TradeAPI) call(method string, v interface{}, params map[string]strin
 	return marshalResponse(resp, v)
 }
 
+// notSentError wraps errors of requests which failed before they were sent, e.g. nonce or rate limiter errors, so that PlaceOrder knows that the order was not created.
+type notSentError struct {
+	err error
+}
+
+func (e notSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e notSentError) Unwrap() error {
+	return e.err
+}
+
+// post signs and sends the request while holding the lock, so that no other request gets a nonce before this one reaches the server.
+// Errors before the request is handed to the HTTP client are wrapped in notSentError.
+func (tapi *TradeAPI) post(ctx context.Context, method string, params map[string]string) (*http.Response, error) {
+	client := clientOrDefault(tapi.client)
+	if err := client.TradeLimiter.Wait(ctx); err != nil {
+		return nil, notSentError{err}
+	}
+
+	state := tapi.shared()
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	postData, err := tapi.encodePostData(method, params)
+	if err != nil {
+		return nil, notSentError{err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", client.tradeURL(), bytes.NewBufferString(postData))
+
+	if err != nil {
+		return nil, notSentError{err}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, notSentError{err}
+	}
+
+	req.Header.Add("Key", tapi.API_KEY)
+	req.Header.Add("Sign", sign(tapi.API_SECRET, postData))
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Content-Length", strconv.Itoa(len(postData)))
+
+	return client.do(req)
+}
+
 func marshalResponse(resp *http.Response, v interface{}) error {
 	// read the response
 	defer resp.Body.Close()
@@ -323,32 +533,27 @@ func marshalResponse(resp *http.Response, v interface{}) error {
 		return err
 	}
 
+	if resp.StatusCode != http.StatusOK {
+		return StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: bytes}
+	}
+
 	data := Response{}
 
 	if err = json.Unmarshal(bytes, &data); err != nil {
-		return err
+		return DecodeError{Body: bytes, Err: err}
 	}
 
 	if data.Success == 1 {
 		if err = json.Unmarshal(data.Return, &v); err != nil {
-			return err
+			return DecodeError{Body: bytes, Err: err}
 		}
 	} else {
-		return TradeError{data.Error}
+		return TradeError{Message: data.Error}
 	}
 
 	return nil
 }
 
-// custom error type for server/trading errors
-type TradeError struct {
-	msg string
-}
-
-func (e TradeError) Error() string {
-	return fmt.Sprintf("trading error: %v", e.msg)
-}
-
 // historyFilterParams creates map[string]string mapping of HistoryFilter
 func historyFilterParams(filter HistoryFilter) map[string]string {
 	params := make(map[string]string, 0)
@@ -32,8 +32,8 @@ func TestAccountInfo(t *testing.T) {
 		Convey("Account information fields should be returned", func() {
 			So(info, ShouldHaveSameTypeAs, AccountInfo{})
 			So(info.TransactionCount, ShouldEqual, 0)
-			So(info.ServerTime, ShouldBeGreaterThan, 0)
-			So(info.Funds["btc"], ShouldBeGreaterThanOrEqualTo, 0)
+			So(info.ServerTime.Unix(), ShouldBeGreaterThan, 0)
+			So(info.Funds["btc"].Sign(), ShouldBeGreaterThanOrEqualTo, 0)
 		})
 	})
 
@@ -48,7 +48,7 @@ func TestActiveOrders(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'no orders'", func() {
-				So(err, ShouldResemble, TradeError{msg: "no orders"})
+				So(err, ShouldResemble, TradeError{Message: "no orders"})
 			})
 		} else {
 			Convey("If no error is returned, 'order' should have length", func() {
@@ -67,7 +67,7 @@ func TestOrderTrade(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'not enough USD'", func() {
-				So(err, ShouldResemble, TradeError{msg: "It is not enough USD for purchase"})
+				So(err, ShouldResemble, TradeError{Message: "It is not enough USD for purchase"})
 			})
 		} else {
 			Convey("If no error is returned, 'btc_usd' amount should appear", func() {
@@ -87,12 +87,12 @@ func TestOrderInfo(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'invalid order'", func() {
-				So(err, ShouldResemble, TradeError{msg: "invalid order"})
+				So(err, ShouldResemble, TradeError{Message: "invalid order"})
 			})
 		} else {
 			Convey("If no error is returned, order information should be returned", func() {
 				So(orderResponse[orderID], ShouldNotBeNil)
-				So(orderResponse[orderID].Amount, ShouldBeGreaterThanOrEqualTo, 0)
+				So(orderResponse[orderID].Amount.Sign(), ShouldBeGreaterThanOrEqualTo, 0)
 			})
 		}
 	})
@@ -108,7 +108,7 @@ func TestCancelOrder(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'bad status'", func() {
-				So(err, ShouldResemble, TradeError{msg: "bad status"})
+				So(err, ShouldResemble, TradeError{Message: "bad status"})
 			})
 		} else {
 			Convey("If no error is returned, same order id should be returned", func() {
@@ -129,7 +129,7 @@ func TestTradeHistory(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'no trades'", func() {
-				So(err, ShouldResemble, TradeError{msg: "no trades"})
+				So(err, ShouldResemble, TradeError{Message: "no trades"})
 			})
 		}
 
@@ -151,7 +151,7 @@ func TestTransactionHistory(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'no transactions'", func() {
-				So(err, ShouldResemble, TradeError{msg: "no transactions"})
+				So(err, ShouldResemble, TradeError{Message: "no transactions"})
 			})
 		}
 
@@ -172,12 +172,12 @@ func TestWithdrawCoin(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'api permission'", func() {
-				So(err, ShouldResemble, TradeError{msg: "api key dont have withdraw permission"})
+				So(err, ShouldResemble, TradeError{Message: "api key dont have withdraw permission"})
 			})
 		} else {
 			Convey("If no error is returned, withdraw response should be returned", func() {
 				So(response.TransactionID, ShouldBeGreaterThan, 0)
-				So(response.AmountSent, ShouldBeGreaterThanOrEqualTo, 0)
+				So(response.AmountSent.Sign(), ShouldBeGreaterThanOrEqualTo, 0)
 			})
 		}
 	})
@@ -193,7 +193,7 @@ func TestCreateCoupon(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'api permission'", func() {
-				So(err, ShouldResemble, TradeError{msg: "api key dont have coupon permission"})
+				So(err, ShouldResemble, TradeError{Message: "api key dont have coupon permission"})
 			})
 		} else {
 			Convey("If no error is returned, withdraw response should be returned", func() {
@@ -214,7 +214,7 @@ func TestRedeemCoupon(t *testing.T) {
 
 		if err != nil {
 			Convey("If error is returned, it should be 'api permission'", func() {
-				So(err, ShouldResemble, TradeError{msg: "api key dont have coupon permission"})
+				So(err, ShouldResemble, TradeError{Message: "api key dont have coupon permission"})
 			})
 		} else {
 			Convey("If no error is returned, withdraw response should be returned", func() {
@@ -0,0 +1,177 @@
+package wex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultTradesLimit is the number of trades returned by Trades when no limit is given.
+const defaultTradesLimit = 150
+
+// TradeEvent is a trade of a pair received by TradeStream.
+type TradeEvent struct {
+	Pair Pair
+	TradeItem
+}
+
+// TradeGap reports that trades of a pair may have been missed between two polls, because every trade of a full batch was new.
+// Trades with TID between AfterTID and BeforeTID were not received.
+type TradeGap struct {
+	Pair      Pair
+	AfterTID  int64
+	BeforeTID int64
+}
+
+// TradeStream polls PublicAPI.Trades for pairs and delivers each trade once, in chronological order, to subscribers.
+// Its methods are safe for concurrent use.
+type TradeStream struct {
+	// OnError is called with errors of Trades requests. Polling continues after errors.
+	OnError func(err error)
+	// OnGap is called when trades may have been missed, e.g. when more than limit trades happened between polls.
+	OnGap func(gap TradeGap)
+	// DropWhenFull makes the stream drop trades for subscribers whose buffers are full instead of waiting for them.
+	// Waiting delays the next poll, which may cause gaps.
+	DropWhenFull bool
+	// OnDrop is called with trades dropped because of DropWhenFull.
+	OnDrop func(event TradeEvent)
+
+	api      *PublicAPI
+	pairs    []Pair
+	interval time.Duration
+	limit    int
+
+	mu          sync.Mutex
+	lastTID     map[Pair]int64
+	subscribers []chan TradeEvent
+}
+
+// NewTradeStream creates a TradeStream polling the last limit trades of the pairs every interval.
+func NewTradeStream(api *PublicAPI, pairs []Pair, interval time.Duration, limit int) *TradeStream {
+	return &TradeStream{
+		api:      api,
+		pairs:    pairs,
+		interval: interval,
+		limit:    limit,
+		lastTID:  make(map[Pair]int64, len(pairs)),
+	}
+}
+
+// Subscribe returns a channel receiving new trades, buffered by buffer trades.
+// The first poll delivers the trades returned by the server. The channel is closed when Run returns.
+func (s *TradeStream) Subscribe(buffer int) <-chan TradeEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan TradeEvent, buffer)
+	s.subscribers = append(s.subscribers, ch)
+	return ch
+}
+
+// Run polls the trades until ctx is done and returns the context error.
+func (s *TradeStream) Run(ctx context.Context) error {
+	defer s.closeSubscribers()
+	return poll(ctx, s.interval, s.Poll, s.OnError)
+}
+
+// Poll requests the trades once and delivers the trades not delivered before to subscribers.
+func (s *TradeStream) Poll(ctx context.Context) error {
+	trades, err := s.api.TradesContext(ctx, s.pairs, s.limit)
+	if err != nil {
+		return err
+	}
+
+	limit := s.limit
+	if limit <= 0 {
+		limit = defaultTradesLimit
+	}
+
+	var events []TradeEvent
+	var gaps []TradeGap
+
+	s.mu.Lock()
+	for _, pair := range s.pairs {
+		batch, ok := trades[pair]
+		if !ok {
+			continue
+		}
+		last, seen := s.lastTID[pair]
+		oldest, newest := int64(0), last
+		fresh := 0
+		for _, trade := range batch {
+			if oldest == 0 || trade.TID < oldest {
+				oldest = trade.TID
+			}
+			if seen && trade.TID <= last {
+				continue
+			}
+			fresh++
+			if trade.TID > newest {
+				newest = trade.TID
+			}
+			events = append(events, TradeEvent{Pair: pair, TradeItem: trade})
+		}
+		if seen && fresh > 0 && fresh >= limit && oldest > last {
+			gaps = append(gaps, TradeGap{Pair: pair, AfterTID: last, BeforeTID: oldest})
+		}
+		if fresh > 0 {
+			s.lastTID[pair] = newest
+		}
+	}
+	subscribers := append([]chan TradeEvent(nil), s.subscribers...)
+	s.mu.Unlock()
+
+	if s.OnGap != nil {
+		for _, gap := range gaps {
+			s.OnGap(gap)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		ti, tj := events[i].Timestamp, events[j].Timestamp
+		if !ti.Equal(tj.Time) {
+			return ti.Before(tj.Time)
+		}
+		return events[i].TID < events[j].TID
+	})
+
+	for _, event := range events {
+		for _, ch := range subscribers {
+			if err := s.send(ctx, ch, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *TradeStream) send(ctx context.Context, ch chan TradeEvent, event TradeEvent) error {
+	if s.DropWhenFull {
+		select {
+		case ch <- event:
+		default:
+			if s.OnDrop != nil {
+				s.OnDrop(event)
+			}
+		}
+		return nil
+	}
+
+	select {
+	case ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *TradeStream) closeSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+}
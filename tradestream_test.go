@@ -0,0 +1,83 @@
+package wex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTradeStream(t *testing.T) {
+
+	Convey("Trade stream polling a server", t, func() {
+
+		var mu sync.Mutex
+		body := `{
+			"btc_usd":[{"type":"bid","price":101,"amount":1,"tid":12,"timestamp":1500000020},{"type":"ask","price":100,"amount":1,"tid":10,"timestamp":1500000010}],
+			"ltc_usd":[{"type":"bid","price":50,"amount":2,"tid":11,"timestamp":1500000015}]}`
+		set := func(b string) {
+			mu.Lock()
+			defer mu.Unlock()
+			body = b
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		api := NewPublicAPI(WithPublicURL(server.URL))
+		stream := NewTradeStream(api, []Pair{"btc_usd", "ltc_usd"}, time.Hour, 2)
+		var gaps []TradeGap
+		stream.OnGap = func(gap TradeGap) { gaps = append(gaps, gap) }
+		trades := stream.Subscribe(16)
+
+		So(stream.Poll(context.Background()), ShouldBeNil)
+
+		Convey("Trades should be delivered in chronological order", func() {
+			So(len(trades), ShouldEqual, 3)
+			So((<-trades).TID, ShouldEqual, 10)
+			event := <-trades
+			So(event.TID, ShouldEqual, 11)
+			So(event.Pair, ShouldEqual, Pair("ltc_usd"))
+			So((<-trades).TID, ShouldEqual, 12)
+		})
+
+		Convey("Overlapping batches should be de-duplicated", func() {
+			for len(trades) > 0 {
+				<-trades
+			}
+			set(`{
+				"btc_usd":[{"type":"ask","price":102,"amount":1,"tid":13,"timestamp":1500000030},{"type":"bid","price":101,"amount":1,"tid":12,"timestamp":1500000020}],
+				"ltc_usd":[{"type":"bid","price":50,"amount":2,"tid":11,"timestamp":1500000015}]}`)
+			So(stream.Poll(context.Background()), ShouldBeNil)
+			So(len(trades), ShouldEqual, 1)
+			So((<-trades).TID, ShouldEqual, 13)
+			So(gaps, ShouldBeEmpty)
+		})
+
+		Convey("Full batches of new trades should be reported as gaps", func() {
+			set(`{"btc_usd":[{"type":"ask","price":103,"amount":1,"tid":21,"timestamp":1500000050},{"type":"ask","price":102,"amount":1,"tid":20,"timestamp":1500000040}]}`)
+			So(stream.Poll(context.Background()), ShouldBeNil)
+			So(gaps, ShouldResemble, []TradeGap{{Pair: "btc_usd", AfterTID: 12, BeforeTID: 20}})
+		})
+
+		Convey("Trades should be dropped for full subscribers when configured", func() {
+			stream.DropWhenFull = true
+			dropped := 0
+			stream.OnDrop = func(TradeEvent) { dropped++ }
+			full := stream.Subscribe(0)
+
+			set(`{"btc_usd":[{"type":"ask","price":102,"amount":1,"tid":13,"timestamp":1500000030}]}`)
+			So(stream.Poll(context.Background()), ShouldBeNil)
+			So(dropped, ShouldEqual, 1)
+			So(len(full), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,154 @@
+package wex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultAmountPlaces is the number of decimal places of order amounts accepted by the exchange for pairs without AmountPlaces.
+const defaultAmountPlaces = 8
+
+// ValidationError is returned when an order does not satisfy the limits of its pair.
+type ValidationError struct {
+	Pair   Pair
+	Field  string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("invalid order for %v: %v %v", e.Pair, e.Field, e.Reason)
+}
+
+// Is reports whether the target is ErrInvalidOrder.
+func (e ValidationError) Is(target error) bool {
+	return target == ErrInvalidOrder
+}
+
+// NormalizeOrder rounds rate of the order to the decimal places of the pair, down for buys and up for sells so that the order never gets a worse price,
+// and truncates its amount to the amount places of the pair. It then checks that the pair is not hidden and rate and amount are within the limits of the pair.
+func (pair InfoPair) NormalizeOrder(order Order) (Order, error) {
+	invalid := func(field string, format string, args ...interface{}) (Order, error) {
+		return order, ValidationError{Pair: order.Pair, Field: field, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if !order.Type.Valid() {
+		return invalid("type", "%q is neither buy nor sell", order.Type)
+	}
+	if pair.Hidden {
+		return invalid("pair", "is hidden")
+	}
+
+	order.Rate = roundRate(order.Rate, pair.DecimalPlaces, order.Type)
+	amountPlaces := pair.AmountPlaces
+	if amountPlaces == 0 {
+		amountPlaces = defaultAmountPlaces
+	}
+	order.Amount = order.Amount.Truncate(amountPlaces)
+
+	if order.Rate.Sign() <= 0 || order.Rate.Cmp(pair.MinPrice) < 0 {
+		return invalid("rate", "%v is below the minimum price %v", order.Rate, pair.MinPrice)
+	}
+	if !pair.MaxPrice.IsZero() && order.Rate.Cmp(pair.MaxPrice) > 0 {
+		return invalid("rate", "%v is above the maximum price %v", order.Rate, pair.MaxPrice)
+	}
+	if order.Amount.Sign() <= 0 || order.Amount.Cmp(pair.MinAmount) < 0 {
+		return invalid("amount", "%v is below the minimum amount %v", order.Amount, pair.MinAmount)
+	}
+	return order, nil
+}
+
+// roundRate rounds a positive rate to places, down for buys and up for sells.
+func roundRate(rate Decimal, places int, orderType OrderType) Decimal {
+	rounded := rate.Truncate(places)
+	if orderType == Sell && rounded.Cmp(rate) < 0 {
+		rounded = rounded.Add(NewDecimal(1, -places))
+	}
+	return rounded
+}
+
+// NormalizeOrder normalizes the order with the limits of its pair as InfoPair.NormalizeOrder does.
+func (info Info) NormalizeOrder(order Order) (Order, error) {
+	pair, ok := info.Pairs[order.Pair]
+	if !ok {
+		return order, ValidationError{Pair: order.Pair, Field: "pair", Reason: "is not an active pair"}
+	}
+	return pair.NormalizeOrder(order)
+}
+
+// WithOrderValidation makes Trade API normalize and validate orders with the pair limits from PublicAPI.Info before sending them.
+// Pair limits are cached for the given duration.
+func WithOrderValidation(cacheFor time.Duration) Option {
+	return func(c *Client) {
+		c.pairInfo = &pairInfoCache{ttl: cacheFor}
+	}
+}
+
+// pairInfoCache keeps the result of PublicAPI.Info for order validation.
+type pairInfoCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	info    Info
+	fetched time.Time
+}
+
+// get returns the cached Info, fetching it when it is expired or does not contain the pair, e.g. a pair listed after the last fetch.
+// The lock is not held while fetching, so that concurrent orders for cached pairs are not delayed.
+func (c *pairInfoCache) get(ctx context.Context, client *Client, pair Pair) (Info, error) {
+	c.mu.Lock()
+	info, fetched := c.info, c.fetched
+	c.mu.Unlock()
+
+	if !fetched.IsZero() && time.Since(fetched) < c.ttl {
+		if _, ok := info.Pairs[pair]; ok {
+			return info, nil
+		}
+	}
+
+	api := PublicAPI{client: client}
+	info, err := api.InfoContext(ctx)
+	if err != nil {
+		return Info{}, err
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.After(c.fetched) {
+		c.info, c.fetched = info, now
+	}
+	return info, nil
+}
+
+// NormalizeOrder normalizes the order with the pair limits from PublicAPI.Info as Info.NormalizeOrder does.
+// Pair limits are cached if order validation is enabled with WithOrderValidation.
+func (tapi *TradeAPI) NormalizeOrder(order Order) (Order, error) {
+	return tapi.NormalizeOrderContext(context.Background(), order)
+}
+
+// NormalizeOrderContext provides NormalizeOrder capability with a context for cancellation and deadlines.
+func (tapi *TradeAPI) NormalizeOrderContext(ctx context.Context, order Order) (Order, error) {
+	client := clientOrDefault(tapi.client)
+
+	var info Info
+	var err error
+	if client.pairInfo != nil {
+		info, err = client.pairInfo.get(ctx, client, order.Pair)
+	} else {
+		info, err = (&PublicAPI{client: client}).InfoContext(ctx)
+	}
+	if err != nil {
+		return order, err
+	}
+	return info.NormalizeOrder(order)
+}
+
+// validateOrder normalizes the order if order validation is enabled.
+func (tapi *TradeAPI) validateOrder(ctx context.Context, order Order) (Order, error) {
+	if clientOrDefault(tapi.client).pairInfo == nil {
+		return order, nil
+	}
+	return tapi.NormalizeOrderContext(ctx, order)
+}
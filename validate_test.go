@@ -0,0 +1,145 @@
+package wex
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOrderValidation(t *testing.T) {
+
+	info := Info{Pairs: map[Pair]InfoPair{
+		"btc_usd": {DecimalPlaces: 3, MinPrice: MustParseDecimal("0.1"), MaxPrice: NewDecimalFromInt(400000), MinAmount: MustParseDecimal("0.001")},
+		"old_usd": {DecimalPlaces: 3, MinPrice: MustParseDecimal("0.1"), MinAmount: MustParseDecimal("0.001"), Hidden: true},
+		"eth_usd": {DecimalPlaces: 2, MinPrice: MustParseDecimal("0.1"), MinAmount: MustParseDecimal("0.01"), AmountPlaces: 2},
+	}}
+	order := func(pair Pair, orderType OrderType, rate string, amount string) Order {
+		return Order{Pair: pair, Type: orderType, Rate: MustParseDecimal(rate), Amount: MustParseDecimal(amount)}
+	}
+	field := func(err error) string {
+		var validationErr ValidationError
+		if errors.As(err, &validationErr) {
+			return validationErr.Field
+		}
+		return ""
+	}
+
+	Convey("Orders should be normalized to the precision of the pair", t, func() {
+		normalized, err := info.NormalizeOrder(order("btc_usd", "buy", "900.12345", "0.123456789"))
+		So(err, ShouldBeNil)
+		So(normalized.Rate.String(), ShouldEqual, "900.123")
+		So(normalized.Amount.String(), ShouldEqual, "0.12345678")
+
+		normalized, err = info.NormalizeOrder(order("btc_usd", "buy", "900.1239", "1"))
+		So(err, ShouldBeNil)
+		So(normalized.Rate.String(), ShouldEqual, "900.123")
+
+		normalized, err = info.NormalizeOrder(order("btc_usd", "sell", "900.1231", "1"))
+		So(err, ShouldBeNil)
+		So(normalized.Rate.String(), ShouldEqual, "900.124")
+
+		normalized, err = info.NormalizeOrder(order("btc_usd", "sell", "900.123", "1"))
+		So(err, ShouldBeNil)
+		So(normalized.Rate.String(), ShouldEqual, "900.123")
+
+		normalized, err = info.NormalizeOrder(order("eth_usd", "buy", "300", "1.23456"))
+		So(err, ShouldBeNil)
+		So(normalized.Amount.String(), ShouldEqual, "1.23")
+	})
+
+	Convey("Orders outside the limits of the pair should be rejected", t, func() {
+		_, err := info.NormalizeOrder(order("btc_usd", "buy", "0.01", "1"))
+		So(field(err), ShouldEqual, "rate")
+		So(errors.Is(err, ErrInvalidOrder), ShouldBeTrue)
+
+		_, err = info.NormalizeOrder(order("btc_usd", "sell", "500000", "1"))
+		So(field(err), ShouldEqual, "rate")
+
+		_, err = info.NormalizeOrder(order("btc_usd", "sell", "900", "0.0001"))
+		So(field(err), ShouldEqual, "amount")
+
+		_, err = info.NormalizeOrder(order("btc_usd", "hold", "900", "1"))
+		So(field(err), ShouldEqual, "type")
+
+		_, err = info.NormalizeOrder(order("old_usd", "buy", "900", "1"))
+		So(field(err), ShouldEqual, "pair")
+
+		_, err = info.NormalizeOrder(order("xyz_usd", "buy", "900", "1"))
+		So(field(err), ShouldEqual, "pair")
+		So(err.Error(), ShouldEqual, "invalid order for xyz_usd: pair is not an active pair")
+	})
+
+	Convey("Trade API with order validation", t, func() {
+
+		var mu sync.Mutex
+		infoRequests := 0
+		pairs := `"btc_usd":{"decimal_places":3,"min_price":0.1,"max_price":400000,"min_amount":0.001,"hidden":0,"fee":0.2}`
+		var sent url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Method == "GET" {
+				infoRequests++
+				w.Write([]byte(`{"server_time":1500000000,"pairs":{` + pairs + `}}`))
+				return
+			}
+			body, _ := ioutil.ReadAll(r.Body)
+			sent, _ = url.ParseQuery(string(body))
+			w.Write([]byte(`{"success":1,"return":{"order_id":1}}`))
+		}))
+		defer server.Close()
+
+		tapi := NewTradeAPI("key", "secret", WithPublicURL(server.URL), WithTradeURL(server.URL), WithOrderValidation(time.Minute))
+
+		Convey("Normalized order should be sent and pair limits cached", func() {
+			_, err := tapi.TradeOrder(order("btc_usd", "buy", "900.12345", "1.5"))
+			So(err, ShouldBeNil)
+			_, err = tapi.Trade("btc_usd", "sell", 900.5, 0.1234567891)
+			So(err, ShouldBeNil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			So(sent.Get("rate"), ShouldEqual, "900.5")
+			So(sent.Get("amount"), ShouldEqual, "0.12345678")
+			So(infoRequests, ShouldEqual, 1)
+		})
+
+		Convey("Pair listed after caching should be fetched once", func() {
+			_, err := tapi.TradeOrder(order("btc_usd", "buy", "900", "1"))
+			So(err, ShouldBeNil)
+
+			mu.Lock()
+			pairs += `,"eth_usd":{"decimal_places":2,"min_price":0.1,"max_price":10000,"min_amount":0.01,"hidden":0,"fee":0.2}`
+			mu.Unlock()
+			_, err = tapi.TradeOrder(order("eth_usd", "buy", "300", "1"))
+			So(err, ShouldBeNil)
+			_, err = tapi.TradeOrder(order("eth_usd", "sell", "300", "1"))
+			So(err, ShouldBeNil)
+			_, err = tapi.TradeOrder(order("xyz_usd", "sell", "300", "1"))
+			So(errors.Is(err, ErrInvalidOrder), ShouldBeTrue)
+
+			mu.Lock()
+			defer mu.Unlock()
+			So(infoRequests, ShouldEqual, 3)
+		})
+
+		Convey("Invalid order should not be sent", func() {
+			_, err := tapi.TradeOrder(order("btc_usd", "buy", "0.01", "1"))
+			So(errors.Is(err, ErrInvalidOrder), ShouldBeTrue)
+
+			_, err = tapi.PlaceOrder(order("btc_usd", "buy", "0.01", "1"))
+			So(errors.Is(err, ErrOrderNotPlaced), ShouldBeTrue)
+
+			mu.Lock()
+			defer mu.Unlock()
+			So(sent, ShouldBeNil)
+		})
+	})
+}
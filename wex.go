@@ -13,15 +13,15 @@
 //
 //		api := wex.API{}
 //
-//		ticker, err := api.Public.Ticker([]string{"btc_usd"})
+//		ticker, err := api.Public.Ticker([]wex.Pair{"btc_usd"})
 //		if err == nil {
-//			fmt.Printf("BTC buy price: %.3f \n", ticker["btc_usd"].Buy)
-//			fmt.Printf("BTC sell price: %.3f \n", ticker["btc_usd"].Sell)
+//			fmt.Printf("BTC buy price: %s \n", ticker["btc_usd"].Buy)
+//			fmt.Printf("BTC sell price: %s \n", ticker["btc_usd"].Sell)
 //		}
 //
 //		info, err := api.Trade.GetInfoAuth("API_KEY", "API_SECRET")
 //		if err == nil {
-//			fmt.Printf("BTC amount: %.3f \n", info.Funds["btc"])
+//			fmt.Printf("BTC amount: %s \n", info.Funds["btc"])
 //		}
 // 	}
 package wex